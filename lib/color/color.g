@@ -2,19 +2,13 @@ is color
 
 include "math"
 
-class RGB {
+class RGB(r, g, b) {
 	float r;
 	float g;
 	float b;
 }
 
-func new_rgb(float r, float g, float b) RGB {
-	RGB col;
-	col.r = r;
-	col.g = g;
-	col.b = b;	
-	return col;
-}
+func new_rgb(float r, float g, float b) RGB = RGB(r, g, b);
 
 
 func hsv_to_rgb(float h, float s, float v) RGB {
@@ -25,5 +25,6 @@ void xfree(void *ptr);
 void *xmalloc(size_t size);
 void *xcalloc(unsigned count, unsigned size);
 void *xrealloc(void *ptr, size_t newsize);
+void *xgrow(void *ptr, size_t used, size_t newsize);
 
 #endif
\ No newline at end of file
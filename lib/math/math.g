@@ -2,6 +2,9 @@ is math
 
 link "math.c"
 
+const PI = 3.14159265358979323846264338327950288
+const E = 2.71828182845904523536028747135266250
+
 # Here are alot of external functions that
 # link to the c library
 func acos(float x) float ...
@@ -18,6 +21,16 @@ func fabs(float x) float ...
 func floor(float x) float ...
 func fmod(float x, float y) float ...
 
+# single precision versions of the above
+func cosf(f32 x) f32 ...
+func sinf(f32 x) f32 ...
+func tanf(f32 x) f32 ...
+func powf(f32 x, f32 y) f32 ...
+func sqrtf(f32 x) f32 ...
+func ceilf(f32 x) f32 ...
+func fabsf(f32 x) f32 ...
+func floorf(f32 x) f32 ...
+
 
 func rand() int ...
 func srand(int seed) ...
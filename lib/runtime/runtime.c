@@ -4,6 +4,7 @@
 #include <stdarg.h>
 #include <stdio.h>
 #include <stdlib.h>
+#include <string.h>
 
 void exit_handle(void) { GC_gcollect(); }
 
@@ -23,6 +24,15 @@ void fatalf(int err, char *fmt, ...) {
   exit(err);
 }
 
+// Called when an array or slice is indexed out of it's bounds. `where` is the
+// file and line of the subscript
+void __bounds_panic(char *where, long index, long length) {
+  fflush(stdout);
+  fprintf(stderr, "%s: index out of range [%ld] with length %ld\n", where, index,
+          length);
+  abort();
+}
+
 char *__runtime_str_format(char *fmt, ...) {
   va_list checkArgs;
   va_start(checkArgs, fmt);
@@ -36,4 +46,72 @@ char *__runtime_str_format(char *fmt, ...) {
   vsnprintf(buffer, size + 1, fmt, args);
   va_end(args);
   return buffer;
-}
\ No newline at end of file
+}
+
+// LLVM lowers conversions between f16 and f32 to these calls, which are
+// normally provided by compiler-rt. They are defined weakly here for
+// toolchains that link against libgcc instead.
+__attribute__((weak)) float __gnu_h2f_ieee(unsigned short h) {
+  unsigned int sign = (unsigned int)(h & 0x8000) << 16;
+  unsigned int exp = (h >> 10) & 0x1f;
+  unsigned int mant = h & 0x3ff;
+  unsigned int bits;
+
+  if (exp == 0x1f) {
+    bits = sign | 0x7f800000 | (mant << 13);
+  } else if (exp == 0) {
+    if (mant == 0) {
+      bits = sign;
+    } else {
+      // Subnormal halfs are normal floats
+      exp = 127 - 15 + 1;
+      while (!(mant & 0x400)) {
+        mant <<= 1;
+        exp--;
+      }
+      bits = sign | (exp << 23) | ((mant & 0x3ff) << 13);
+    }
+  } else {
+    bits = sign | ((exp + 127 - 15) << 23) | (mant << 13);
+  }
+
+  float f;
+  memcpy(&f, &bits, sizeof(f));
+  return f;
+}
+
+__attribute__((weak)) unsigned short __gnu_f2h_ieee(float f) {
+  unsigned int bits;
+  memcpy(&bits, &f, sizeof(bits));
+  unsigned short sign = (bits >> 16) & 0x8000;
+  int exp = (int)((bits >> 23) & 0xff) - 127 + 15;
+  unsigned int mant = bits & 0x7fffff;
+
+  // Infinity and NaN
+  if (((bits >> 23) & 0xff) == 0xff)
+    return sign | 0x7c00 | (mant ? 0x200 : 0);
+  if (exp >= 0x1f)
+    return sign | 0x7c00;
+
+  // Too small for a normal half, so it is rounded to a subnormal one
+  if (exp <= 0) {
+    if (exp < -10)
+      return sign;
+    mant |= 0x800000;
+    int shift = 14 - exp;
+    unsigned int half = mant >> shift;
+    unsigned int rem = mant & ((1u << shift) - 1);
+    unsigned int mid = 1u << (shift - 1);
+    if (rem > mid || (rem == mid && (half & 1)))
+      half++;
+    return sign | half;
+  }
+
+  // Round to nearest even. A carry out of the mantissa correctly bumps the
+  // exponent, up to infinity.
+  unsigned int half = ((unsigned int)exp << 10) | (mant >> 13);
+  unsigned int rem = mant & 0x1fff;
+  if (rem > 0x1000 || (rem == 0x1000 && (half & 1)))
+    half++;
+  return sign | half;
+}
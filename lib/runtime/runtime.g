@@ -8,6 +8,7 @@ link "xmalloc.c"
 # safer, gc friendly memory functions.
 func xmalloc(int size) byte* ...
 func xrealloc(byte* ptr, int size) byte* ...
+func xgrow(byte* ptr, long used, long size) byte* ...
 func memcpy(byte* dest, byte* src, int length) ...
 func xmalloc_size(byte* ptr) long ...
 func __init_c_runtime() ...
@@ -39,6 +40,9 @@ func raw_copy(byte* source, int len) byte* {
 
 func __runtime_str_format(string format, ...) string ...
 
+# called by bounds checked subscripts when the index is out of range
+func __bounds_panic(byte* where, long index, long length) ...
+
 
 func __init_runtime() {
 	# this function doesn't do anything right now, but it does
@@ -47,6 +51,24 @@ func __init_runtime() {
 	__init_c_runtime();
 }
 
+# the kinds of types a TypeInfo can describe
+enum TypeKind {
+	Void,
+	Bool,
+	Int,
+	Uint,
+	Float,
+	String,
+	Pointer,
+	Slice,
+	Array,
+	Class,
+	Enum,
+	Tuple,
+	Func,
+	Protocol,
+}
+
 # typeinfo is what is returned from the info(T) call.
 # The instance contains information about the type T
 class TypeInfo {
@@ -55,6 +77,31 @@ class TypeInfo {
 
 	# the name of the type
 	string name
+
+	# the names of the variants of an enum, in the order of their tags.
+	# Other types have no variants
+	string* variants
+	int variant_count
+
+	# what kind of type it is, and it's alignment in bytes
+	TypeKind kind
+	int align
+
+	# the type pointers point to, and the type of the elements of slices and arrays
+	TypeInfo* elem
+
+	# the fields of a class, in the order they are laid out
+	FieldInfo* fields
+	int field_count
+}
+
+# a field of a class, as described by it's TypeInfo
+class FieldInfo {
+	string name
+
+	# the offset of the field from the start of the class in bytes
+	int offset
+	TypeInfo* type
 }
 
 
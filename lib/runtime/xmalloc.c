@@ -139,6 +139,16 @@ void *xrealloc(void *ptr, size_t newsize) {
   return (void *)(newptr + PRELUDE_SIZE);
 }
 
+// Grow some storage to newsize bytes, keeping the first used bytes of it.
+// The storage is always copied into a new block rather than reallocated, as
+// other slices might still point at it and reallocating can free it
+void *xgrow(void *ptr, size_t used, size_t newsize) {
+  void *newptr = xmalloc(newsize);
+  if (ptr != NULL)
+    memcpy(newptr, ptr, used);
+  return newptr;
+}
+
 void *xcalloc(unsigned count, unsigned size) {
   unsigned int n = count * size;
   // Errors should be handled in the xmalloc function
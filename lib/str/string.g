@@ -96,24 +96,38 @@ func hash(string str) long {
 
 
 
+# str:split
 # split str by all characters in sset and return
-# a NULL terminated string buffer
+# a NULL terminated buffer of the pieces between them
 func split(string str, string sset) string* {
-	strSize = info(string).size
-	count = 0
-	splits = mem:get(strSize * count)
+	size = len(str)
+	setSize = len(sset)
 
+	# each byte could end a piece, and the buffer needs room for the NULL
+	string* splits = mem:zero((size + 2) * info(string).size)
+	count = 0
+	start = 0
 
-	string ptr = str;
-	while str[i] {
-		char = str[i]
+	for i = 0; i <= size; i += 1 {
+		# the end of the string ends the last piece
+		bool end = i == size
 
 		# check if the char is in the subset
-		for c = 0; c < len(sset); c += 1 {
-			if 
+		for c = 0; c < setSize; c += 1 {
+			if str[i] == sset[c] {
+				end = true
+			}
 		}
-	}
-
 
+		if end {
+			string piece = mem:zero(i - start + 1)
+			for j = start; j < i; j += 1 {
+				piece[j - start] = str[j]
+			}
+			splits[count] = piece
+			count += 1
+			start = i + 1
+		}
+	}
 	return splits
-}
\ No newline at end of file
+}
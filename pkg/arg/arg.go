@@ -16,6 +16,8 @@ var (
 	DisableEmission       = App.Flag("no-emission", "Disable emission and only run through the syntax checking process").Bool()
 	DisableRuntime        = App.Flag("no-runtime", "Disable calls to the runtime. Warning: garbage collector, etc will be gone. Most standard libraries will not work.").Bool()
 	DisableStringDataCopy = App.Flag("no-dynamic-strings", "Disable the dynamic string copy and replace with static/constant .data section pointers").Bool()
+	DisableBoundsCheck    = App.Flag("no-bounds-check", "Disable the runtime bounds checks on array and slice subscripts").Bool()
+	TargetTriple          = App.Flag("target", "The target triple to compile for, like aarch64-unknown-linux-gnu. Defaults to the target of clang").String()
 	LinkerArgs            = App.Flag("linker-args", "Arguments to pass clang when linking object files").String()
 	EmitASM               = App.Flag("asm", "Emit the asm of the program to the current directory. (will not produce binary)").Bool()
 	EmitLLVM              = App.Flag("llvm", "Emit the llvm of the program to the current directory. (will not produce binary)").Bool()
@@ -24,6 +26,7 @@ var (
 	DumpScopeTree         = App.Flag("dump-scope-tree", "Dump a tree representation of the scope to stdout").Bool()
 	ClangFlags            = App.Flag("clang-flags", "flags to pass into the clang compiler/linker").String()
 	EnableDebug           = App.Flag("debug", "(NOT WORKING) Enable debug information").Short('g').Bool()
+	ErrorFormat           = App.Flag("error-format", "How errors are reported, as text with the source they are about, or as json (one object per line) for editors and CI").Default("text").Enum("text", "json")
 )
 
 // Global arguments accessable throughout the program
@@ -46,6 +49,18 @@ var (
 
 	InfoCMD   = App.Command("info", "Get information about a program (does not compile, just lexes and parses)")
 	InfoInput = InfoCMD.Arg("input", "Geode source file or package").String()
+
+	LSPCMD = App.Command("lsp", "Run a language server for editors, which talks the language server protocol over stdio")
+
+	FmtCMD   = App.Command("fmt", "Format Geode source files, and print them unless --write or --check are given")
+	FmtInput = FmtCMD.Arg("input", "Geode source files, or directories to format the files in").Default(".").Strings()
+	FmtCheck = FmtCMD.Flag("check", "List the files that aren't formatted, and fail if there are any").Bool()
+	FmtWrite = FmtCMD.Flag("write", "Write the formatted source back to the files").Short('w').Bool()
+
+	VetCMD     = App.Command("vet", "Check a package for likely bugs, like unused variables and unreachable code")
+	VetInput   = VetCMD.Arg("input", "Geode source file or package").Default(".").String()
+	VetDisable = VetCMD.Flag("disable", "Turn a check off: unused, unreachable, shadow, missing-return, assign-in-if or pure-result. Can be given more than once").Short('d').Strings()
+	VetOnly    = VetCMD.Flag("only", "Only run a check, which can be given more than once").Strings()
 )
 
 // Parse returns the kingpin command returned by kingpin.MustParse
@@ -56,6 +56,14 @@ func (n ArrayNode) Codegen(prog *Program) (value.Value, error) {
 	}
 	typ := prog.Compiler.PopType()
 
+	if slice, isSlice := typ.(*gtypes.SliceType); isSlice {
+		return n.genSlice(prog, slice, values)
+	}
+
+	if array, isArray := typ.(*types.ArrayType); isArray {
+		return n.genFixed(prog, array, values)
+	}
+
 	if typ == nil {
 		typ = types.NewPointer(values[0].Type())
 	}
@@ -68,7 +76,7 @@ func (n ArrayNode) Codegen(prog *Program) (value.Value, error) {
 	var alloca value.Value
 	// alloca = block.NewAlloca(arrayType)
 
-	length := constant.NewInt(types.I32, int64(n.Length*gtypes.ByteCount(arrayType)))
+	length := constant.NewInt(types.I32, int64(gtypes.ByteCount(arrayType)))
 
 	dyn, err := prog.NewRuntimeFunctionCall("xmalloc", length)
 	if err != nil {
@@ -104,6 +112,53 @@ func (n ArrayNode) Codegen(prog *Program) (value.Value, error) {
 	return arrayStart, nil
 }
 
+// genFixed returns the values of the array as a value of a fixed size array type
+func (n ArrayNode) genFixed(prog *Program, typ *types.ArrayType, values []value.Value) (value.Value, error) {
+	if uint64(len(values)) != typ.Len {
+		return nil, n.Errorf("array of %d elements can't be stored in %s", len(values), prog.typeInfoName(typ))
+	}
+
+	block := prog.Compiler.CurrentBlock()
+	var array value.Value = constant.NewZeroInitializer(typ)
+	for i, val := range values {
+		c, err := createTypeCast(prog, val, typ.ElemType)
+		if err != nil {
+			return nil, err
+		}
+		array = block.NewInsertValue(array, c, uint64(i))
+	}
+	return array, nil
+}
+
+// genSlice stores the values of the array in new storage on the heap, and returns a
+// slice of them
+func (n ArrayNode) genSlice(prog *Program, typ *gtypes.SliceType, values []value.Value) (value.Value, error) {
+	length := constant.NewInt(types.I64, int64(len(values)))
+	if len(values) == 0 {
+		return newSlice(prog.Compiler.CurrentBlock(), constant.NewNull(types.NewPointer(typ.ElemType)), length, length), nil
+	}
+
+	size := constant.NewInt(types.I32, int64(len(values)*gtypes.ByteCount(typ.ElemType)))
+	dyn, err := prog.NewRuntimeFunctionCall("xmalloc", size)
+	if err != nil {
+		return nil, err
+	}
+
+	block := prog.Compiler.CurrentBlock()
+	elems := block.NewBitCast(dyn, types.NewPointer(typ.ElemType))
+	for i, val := range values {
+		c, err := createTypeCast(prog, val, typ.ElemType)
+		if err != nil {
+			return nil, err
+		}
+		offset := gep(elems, constant.NewInt(types.I64, int64(i)))
+		block.Insts = append(block.Insts, offset)
+		block.NewStore(c, offset)
+	}
+
+	return newSlice(block, elems, length, length), nil
+}
+
 func (n ArrayNode) String() string {
 	buff := &bytes.Buffer{}
 	fmt.Fprintf(buff, "ArrayNode")
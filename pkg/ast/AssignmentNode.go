@@ -44,11 +44,21 @@ func (n AssignmentNode) Codegen(prog *Program) (value.Value, error) {
 	if targetType != nil && !types.Equal(val.Type(), targetType) {
 		val, err = createTypeCast(prog, val, targetType)
 		if err != nil {
-			return nil, err
+			return nil, n.Locate(err)
 		}
 	}
 
 	// fmt.Println(val)
 	n.Assignee.GenAssign(prog, val)
+
+	if array, isArray := n.Value.(ArrayNode); isArray {
+		switch assignee := n.Assignee.(type) {
+		case IdentNode:
+			prog.arrayLengths[assignee.Alloca(prog)] = int64(len(array.Elements))
+		case VariableDefnNode:
+			// Declarations with a type, like `int* a = [1, 2, 3]`
+			prog.arrayLengths[assignee.Name.Alloca(prog)] = int64(len(array.Elements))
+		}
+	}
 	return val, nil
 }
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/geode-lang/geode/pkg/gtypes"
 	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/enum"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
@@ -39,36 +41,37 @@ func CreateBinaryOp(intstr, fltstr string, blk *ir.Block, t types.Type, left, ri
 	return val
 }
 
+// The instructions of an operator on signed integers, unsigned integers and floats
 type numericalBinaryOperator struct {
 	I string
+	U string
 	F string
 }
 
 type comparisonOperation struct {
 	I enum.IPred
+	U enum.IPred
 	F enum.FPred
 }
 
 var binaryOperatorTypeMap = map[string]numericalBinaryOperator{
-	"+":  {"add", "fadd"},
-	"-":  {"sub", "fsub"},
-	"*":  {"mul", "fmul"},
-	"/":  {"sdiv", "fdiv"},
-	"%":  {"srem", "frem"},
-	">>": {"lshr", "lshr"},
-	"<<": {"shl", "shl"},
-	"||": {"or", "or"},
-	"&&": {"and", "and"},
-	"^":  {"xor", "xor"},
+	"+":  {"add", "add", "fadd"},
+	"-":  {"sub", "sub", "fsub"},
+	"*":  {"mul", "mul", "fmul"},
+	"/":  {"sdiv", "udiv", "fdiv"},
+	"%":  {"srem", "urem", "frem"},
+	">>": {"ashr", "lshr", "lshr"},
+	"<<": {"shl", "shl", "shl"},
+	"^":  {"xor", "xor", "xor"},
 }
 
 var booleanComparisonOperatorMap = map[string]comparisonOperation{
-	"==": {enum.IPredEQ, enum.FPredOEQ},
-	"!=": {enum.IPredNE, enum.FPredONE},
-	">":  {enum.IPredSGT, enum.FPredOGT},
-	">=": {enum.IPredSGE, enum.FPredOGE},
-	"<":  {enum.IPredSLT, enum.FPredOLT},
-	"<=": {enum.IPredSLE, enum.FPredOLE},
+	"==": {enum.IPredEQ, enum.IPredEQ, enum.FPredOEQ},
+	"!=": {enum.IPredNE, enum.IPredNE, enum.FPredONE},
+	">":  {enum.IPredSGT, enum.IPredUGT, enum.FPredOGT},
+	">=": {enum.IPredSGE, enum.IPredUGE, enum.FPredOGE},
+	"<":  {enum.IPredSLT, enum.IPredULT, enum.FPredOLT},
+	"<=": {enum.IPredSLE, enum.IPredULE, enum.FPredOLE},
 }
 
 // BinaryNode is a representation of a binary operation
@@ -139,24 +142,28 @@ func (n BinaryNode) Codegen(prog *Program) (value.Value, error) {
 	if n.OP == "=" {
 		lhs, ok := n.Left.(Assignable)
 		if !ok {
-			return nil, fmt.Errorf("attempt to assign to a non assignable value '%s' (%T)", n.Left, n.Left)
+			return nil, n.Errorf("attempt to assign to a non assignable value '%s' (%T)", n.Left, n.Left)
 		}
 
 		rhs, ok := n.Right.(Accessable)
 		if !ok {
-			return nil, fmt.Errorf("attempt to assign with a non accessable value '%s'", n.Right)
+			return nil, n.Errorf("attempt to assign with a non accessable value '%s'", n.Right)
 		}
 
 		a := AssignmentNode{}
 		a.Assignee = lhs
 		a.Value = rhs
 		a.NodeType = nodeAssignment
+		a.TokenReference = n.TokenReference
 		return a.Codegen(prog)
 	}
 
 	switch n.OP {
 	case "+=", "-=", "*=", "/=":
-		return CodegenCompoundOperator(prog, n.Left, n.Right, n.OP)
+		val, err := CodegenCompoundOperator(prog, n.Left, n.Right, n.OP)
+		return val, n.Locate(err)
+	case "&&", "||":
+		return n.genShortCircuit(prog)
 	case "+", "-":
 		add := AddSubNode{}
 		add.Left = n.Left
@@ -168,8 +175,7 @@ func (n BinaryNode) Codegen(prog *Program) (value.Value, error) {
 	}
 
 	if n.Left == nil || n.Right == nil {
-		n.SyntaxError()
-		return nil, fmt.Errorf("invalid binary expression")
+		return nil, n.Errorf("invalid binary expression")
 	}
 	// Generate the left and right nodes
 	l, err := n.Left.Codegen(prog)
@@ -199,20 +205,29 @@ func (n BinaryNode) Codegen(prog *Program) (value.Value, error) {
 	l, r, t, resultcast := binaryCast(prog, l, r)
 
 	if l == nil || r == nil {
-		n.SyntaxError()
-		return nil, fmt.Errorf("an operand to a binary operation `%s` was nil and failed to generate", n.OP)
+		return nil, n.Errorf("an operand to a binary operation `%s` was nil and failed to generate", n.OP)
 	}
 
 	blk := prog.Compiler.CurrentBlock()
 
 	var value value.Value
 
+	unsigned := gtypes.IsUnsigned(t)
+
 	if op, valid := binaryOperatorTypeMap[n.OP]; valid {
-		value = CreateBinaryOp(op.I, op.F, blk, t, l, r)
+		intOp := op.I
+		if unsigned {
+			intOp = op.U
+		}
+		value = CreateBinaryOp(intOp, op.F, blk, t, l, r)
 	}
 
 	if op, valid := booleanComparisonOperatorMap[n.OP]; valid {
-		value = createCmp(blk, op.I, op.F, t, l, r)
+		intPred := op.I
+		if unsigned {
+			intPred = op.U
+		}
+		value = createCmp(blk, intPred, op.F, t, l, r)
 	}
 
 	if value == nil {
@@ -231,6 +246,75 @@ func (n BinaryNode) Codegen(prog *Program) (value.Value, error) {
 
 }
 
+// genShortCircuit generates a logical `&&` or `||`. The right hand side is only
+// evaluated if the left hand side doesn't already decide the result, so
+// expressions like `p != nil && p.x > 0` are safe.
+func (n BinaryNode) genShortCircuit(prog *Program) (value.Value, error) {
+	if n.Left == nil || n.Right == nil {
+		return nil, n.Errorf("invalid binary expression")
+	}
+
+	l, err := n.Left.Codegen(prog)
+	if err != nil {
+		return nil, err
+	}
+	l, err = toBool(prog, l)
+	if err != nil {
+		return nil, err
+	}
+
+	// The left hand side might have created blocks of it's own, so
+	// the branch comes from wherever it ended up
+	lhsBlk := prog.Compiler.CurrentBlock()
+	parentFunc := lhsBlk.Parent
+
+	rhsBlk := parentFunc.NewBlock(mangleName("logical_rhs"))
+	endBlk := parentFunc.NewBlock(mangleName("logical_end"))
+
+	// The value of the whole expression if the right hand side is skipped
+	var skipped value.Value
+	if n.OP == "&&" {
+		skipped = constant.False
+		lhsBlk.NewCondBr(l, rhsBlk, endBlk)
+	} else {
+		skipped = constant.True
+		lhsBlk.NewCondBr(l, endBlk, rhsBlk)
+	}
+
+	prog.Compiler.PushBlock(rhsBlk)
+	r, err := n.Right.Codegen(prog)
+	if err != nil {
+		return nil, err
+	}
+	r, err = toBool(prog, r)
+	if err != nil {
+		return nil, err
+	}
+	rhsEndBlk := prog.Compiler.CurrentBlock()
+	rhsEndBlk.NewBr(endBlk)
+
+	prog.Compiler.PushBlock(endBlk)
+	return endBlk.NewPhi(ir.NewIncoming(skipped, lhsBlk), ir.NewIncoming(r, rhsEndBlk)), nil
+}
+
+// toBool converts a value to an i1 by comparing it to zero. Unlike
+// a truncation, this keeps every non zero value true.
+func toBool(prog *Program, v value.Value) (value.Value, error) {
+	t := v.Type()
+	blk := prog.Compiler.CurrentBlock()
+	switch {
+	case types.Equal(t, types.I1):
+		return v, nil
+	case types.IsInt(t):
+		return blk.NewICmp(enum.IPredNE, v, constant.NewInt(t.(*types.IntType), 0)), nil
+	case types.IsFloat(t):
+		return blk.NewFCmp(enum.FPredUNE, v, constant.NewFloat(t.(*types.FloatType), 0)), nil
+	case types.IsPointer(t):
+		return blk.NewICmp(enum.IPredNE, v, constant.NewNull(t.(*types.PointerType))), nil
+	}
+	return nil, fmt.Errorf("unable to use a value of type %s as a boolean", t)
+}
+
 func binaryCast(prog *Program, left, right value.Value) (value.Value, value.Value, types.Type, types.Type) {
 
 	var resultcast types.Type
@@ -244,6 +328,15 @@ func binaryCast(prog *Program, left, right value.Value) (value.Value, value.Valu
 		resultcast = right.Type()
 	}
 
+	// Float literals take the width of the float they are used with, so
+	// `x * 0.5` stays single precision if x is an f32
+	if _, isConst := right.(*constant.Float); isConst && types.IsFloat(left.Type()) {
+		right, _ = createTypeCast(prog, right, left.Type())
+	}
+	if _, isConst := left.(*constant.Float); isConst && types.IsFloat(right.Type()) {
+		left, _ = createTypeCast(prog, left, right.Type())
+	}
+
 	// Right and Left types
 	lt := left.Type()
 	rt := right.Type()
@@ -254,7 +347,8 @@ func binaryCast(prog *Program, left, right value.Value) (value.Value, value.Valu
 	leftPrec := prog.CastPrecidence(lt)
 	rightPrec := prog.CastPrecidence(rt)
 
-	if leftPrec > rightPrec {
+	// Between integers of the same size, unsigned wins
+	if leftPrec > rightPrec || (leftPrec == rightPrec && gtypes.IsUnsigned(lt)) {
 		casted = lt
 		right, _ = createTypeCast(prog, right, lt)
 	} else {
@@ -315,7 +409,6 @@ func (n AddSubNode) Codegen(prog *Program) (value.Value, error) {
 	if err != nil {
 		return nil, err
 	}
-	// TODO: handle unsigned numbers... (maybe)
 	left, right, t, resultcast := binaryCast(prog, l, r)
 
 	// float add/sub operations on numeric types are prefixed with 'f'
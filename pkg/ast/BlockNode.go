@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/geode-lang/geode/pkg/diag"
+
 	"github.com/llir/llvm/ir/value"
 )
 
@@ -22,25 +24,54 @@ func (n BlockNode) NameString() string { return "BlockNode" }
 // Codegen implements Node.Codegen for BlockNode
 func (n BlockNode) Codegen(prog *Program) (value.Value, error) {
 	prog.ScopeDown(n.Token)
+	prog.Compiler.PushDefers()
 
-	for _, node := range n.Nodes {
+	for i, node := range n.Nodes {
 
-		_, err := node.Codegen(prog)
+		// Errors that don't say where they are from are given the statement
+		val, err := node.Codegen(prog)
 		if err != nil {
-			return nil, err
+			return nil, node.Locate(err)
 		}
+		prog.vetStatement(node, val)
 
-		if _, isReturn := node.(ReturnNode); isReturn {
+		if i+1 < len(n.Nodes) && terminates(node) {
+			next := n.Nodes[i+1]
+			d := diag.Warningf(statementSpan(next), "unreachable code")
+			d.Label(node.Span(), "nothing after this runs")
+			prog.warn(VetUnreachable, n.Token.Path(), d)
+		}
+
+		if endsBlock(node) {
 			break
 		}
 	}
 
+	// Returns, breaks and continues run the deferred code themselves, so it
+	// is only run here if the block ends normally
+	if prog.Compiler.CurrentBlock().Term == nil {
+		if err := prog.runDefers(len(prog.Compiler.defers) - 1); err != nil {
+			return nil, err
+		}
+	}
+	prog.Compiler.PopDefers()
+
 	if err := prog.ScopeUp(); err != nil {
 		return nil, err
 	}
 	return prog.Compiler.CurrentBlock(), nil
 }
 
+// endsBlock reports whether a node jumps out of the block it is in,
+// meaning nothing after it in the block can run
+func endsBlock(node Node) bool {
+	switch node.(type) {
+	case ReturnNode, BreakNode, ContinueNode:
+		return true
+	}
+	return false
+}
+
 var blockindentdepth = 0
 
 func (n BlockNode) String() string {
@@ -0,0 +1,85 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/value"
+)
+
+// BreakNode jumps out of the innermost loop, or the loop with the
+// given label, example: `break outer;`
+type BreakNode struct {
+	NodeType
+	TokenReference
+
+	Label string
+}
+
+// NameString implements Node.NameString
+func (n BreakNode) NameString() string { return "BreakNode" }
+
+func (n BreakNode) String() string {
+	if n.Label != "" {
+		return fmt.Sprintf("break %s", n.Label)
+	}
+	return "break"
+}
+
+// Codegen implements Node.Codegen for BreakNode
+func (n BreakNode) Codegen(prog *Program) (value.Value, error) {
+	loop, err := findLoop(prog, "break", n.Label)
+	if err != nil {
+		return nil, n.Locate(err)
+	}
+	if err := prog.runDefers(loop.defers); err != nil {
+		return nil, err
+	}
+	prog.Compiler.CurrentBlock().NewBr(loop.breakBlk)
+	return nil, nil
+}
+
+// ContinueNode jumps to the next iteration of the innermost loop, or
+// the loop with the given label, example: `continue outer;`
+type ContinueNode struct {
+	NodeType
+	TokenReference
+
+	Label string
+}
+
+// NameString implements Node.NameString
+func (n ContinueNode) NameString() string { return "ContinueNode" }
+
+func (n ContinueNode) String() string {
+	if n.Label != "" {
+		return fmt.Sprintf("continue %s", n.Label)
+	}
+	return "continue"
+}
+
+// Codegen implements Node.Codegen for ContinueNode
+func (n ContinueNode) Codegen(prog *Program) (value.Value, error) {
+	loop, err := findLoop(prog, "continue", n.Label)
+	if err != nil {
+		return nil, n.Locate(err)
+	}
+	if err := prog.runDefers(loop.defers); err != nil {
+		return nil, err
+	}
+	prog.Compiler.CurrentBlock().NewBr(loop.continueBlk)
+	return nil, nil
+}
+
+func findLoop(prog *Program, keyword string, label string) (loopBlocks, error) {
+	loop, found := prog.Compiler.FindLoop(label)
+	if found && loop.deferring != prog.Compiler.deferring {
+		return loop, fmt.Errorf("%s out of deferred code", keyword)
+	}
+	if found {
+		return loop, nil
+	}
+	if label != "" {
+		return loop, fmt.Errorf("%s to unknown loop label %q", keyword, label)
+	}
+	return loop, fmt.Errorf("%s statement outside of a loop", keyword)
+}
@@ -1,12 +1,11 @@
 package ast
 
 import (
-	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
 )
 
 // Callable is for the left side of a function call. It has functions for getting the function that it points to, etc...
 type Callable interface {
-	GetFunc(*Program, []types.Type) (*ir.Func, []value.Value, error)
+	GetFunc(*Program, []types.Type) (value.Value, []value.Value, error)
 }
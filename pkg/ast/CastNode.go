@@ -3,7 +3,6 @@ package ast
 import (
 	"fmt"
 
-	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
 )
 
@@ -31,13 +30,10 @@ func (n CastNode) Codegen(prog *Program) (value.Value, error) {
 	if err != nil {
 		return nil, err
 	}
-	t, err := prog.FindType(n.Type.Name)
+	t, err := n.Type.GetType(prog)
 	if err != nil {
 		return nil, err
 	}
-	for i := 0; i < n.Type.PointerLevel; i++ {
-		t = types.NewPointer(t)
-	}
 	return createTypeCast(prog, src, t)
 }
 
@@ -1,11 +1,12 @@
 package ast
 
 import (
-	"bytes"
 	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/geode-lang/geode/pkg/diag"
 	"github.com/geode-lang/geode/pkg/gtypes"
-	"github.com/geode-lang/geode/pkg/util/color"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/types"
@@ -19,8 +20,19 @@ type ClassNode struct {
 
 	Package   *Package
 	Name      string
+	Params    []string
 	Methods   []FunctionNode
 	Variables []VariableDefnNode
+
+	// Generic classes have type parameters, and each instance of
+	// them is it's own class with the type arguments filled in
+	TypeParams []string
+	TypeArgs   []types.Type
+
+	genericName  string // the name of the generic class an instance came from
+	instances    map[string]*ClassNode
+	typ          *gtypes.StructType
+	constructors map[string]*ir.Func
 }
 
 // NameString implements Node.NameString
@@ -40,12 +52,12 @@ func (n ClassNode) NameString() string { return "ClassNode" }
 func (n ClassNode) VerifyCorrectness(prog *Program) error {
 	found, err := prog.FindType(n.Name)
 	if err != nil {
-		return err
+		return n.Locate(err)
 	}
 
 	base, ok := found.(*gtypes.StructType)
 	if !ok {
-		return fmt.Errorf("unable to cast found type %T to a struct for class %q", found, n.Name)
+		return n.Errorf("unable to cast found type %T to a struct for class %q", found, n.Name)
 	}
 
 	for _, f := range n.Variables {
@@ -53,7 +65,7 @@ func (n ClassNode) VerifyCorrectness(prog *Program) error {
 		t := f.Typ.Name
 		ty, err := f.Typ.GetType(prog)
 		if err != nil {
-			return err
+			return f.Locate(err)
 		}
 
 		// Pointer types should be correct
@@ -61,21 +73,28 @@ func (n ClassNode) VerifyCorrectness(prog *Program) error {
 			continue
 		}
 
-		if gtypes.IsStruct(ty) {
+		// Protocols and closures only hold a pointer to their data, so they are correct too
+		if gtypes.IsProtocol(ty) || gtypes.IsClosure(ty) {
+			continue
+		}
+
+		// Enums keep their fields in a payload that isn't laid out yet
+		if gtypes.IsEnum(ty) {
+			continue
+		}
+
+		if structT, isStruct := ty.(*gtypes.StructType); isStruct {
 			// If the type is a direct reference back to the base class, it is invalid. It must be a pointer type
 			if types.Equal(base, ty) {
-				return fmt.Errorf("class '%s' has a circular reference in it's fields. Field '%s' should be a pointer to a '%s' instead", n.Name, f.Name, n.Name)
+				return f.Errorf("class '%s' has a circular reference in its fields. Field '%s' should be a pointer to a '%s' instead", n.Name, f.Name, n.Name)
 			}
 
 			// Now we need to check if the struct has a non-pointer reference back to this class.
 			// that has the same effect.
-			structT := ty.(*gtypes.StructType)
-
 			if contains, _, _ := structContainsTypeAnywhere(structT, base, structT); contains {
-				buff := &bytes.Buffer{}
-				fmt.Fprintf(buff, "* class %s has a field %s of type %s which eventually back references %s (would consume 'infinite' stack memory)\n", color.Green(n.Name), color.Blue(fieldName), color.Red(t), color.Green(n.Name))
-				fmt.Fprintf(buff, "  Either change %s to a pointer or remove the back-reference from %s\n\n", color.Blue(fieldName), color.Red(t))
-				return fmt.Errorf("%s", buff)
+				d := diag.Errorf(f.Span(), "class %s has a field %s of type %s which eventually back references %s (would consume 'infinite' stack memory)", n.Name, fieldName, t, n.Name)
+				d.Label(n.Span(), "%s is declared here", n.Name)
+				return d.Note("either change %s to a pointer or remove the back-reference from %s", fieldName, t)
 			}
 		}
 	}
@@ -87,8 +106,7 @@ func structContainsTypeAnywhere(s *gtypes.StructType, t types.Type, path ...*gty
 		if types.Equal(field, t) {
 			return true, i, path
 		}
-		if gtypes.IsStruct(field) {
-			structType := field.(*gtypes.StructType)
+		if structType, ok := field.(*gtypes.StructType); ok {
 			if contains, index, p := structContainsTypeAnywhere(structType, t, append(path, structType)...); contains {
 				return true, index, p
 			}
@@ -105,10 +123,10 @@ func (n ClassNode) String() string {
 func (n ClassNode) Declare(prog *Program) (value.Value, error) {
 	structDefn := gtypes.NewStruct()
 
+	// The struct is named with the package so classes with the same name
+	// in two packages are different types
 	name := fmt.Sprintf("class.%s:%s", prog.Scope.PackageName, n.Name)
-	structDefn.SetName(name)
-
-	prog.Module.NewTypeDef(n.Name, structDefn)
+	prog.Module.NewTypeDef(name, structDefn)
 
 	scopeName := n.Name
 	if prog.Package.Name != "runtime" {
@@ -163,31 +181,233 @@ func (n ClassNode) Codegen(prog *Program) (value.Value, error) {
 	structDefn.Fields = fields
 	structDefn.Names = fieldnames
 
+	params := map[string]bool{}
+	for _, param := range n.Params {
+		if structDefn.FieldIndex(param) == -1 {
+			return nil, fmt.Errorf("class '%s' has a constructor parameter '%s' which is not one of its fields", n.Name, param)
+		}
+		if params[param] {
+			return nil, n.Errorf("class '%s' has two constructor parameters named '%s'", n.Name, param)
+		}
+		params[param] = true
+	}
+
 	// methodBaseArgs := []VariableDefnNode{thisArg}
 	for _, fn := range n.Methods {
 
 		// Prepend the "this" argument to the function
 		fn.Args = append([]FunctionArg{thisArg}, fn.Args...)
-		fn.Name.Value = fmt.Sprintf("%s:%s.%s", prog.Package.Name, n.Name, fn.Name)
+		name := fmt.Sprintf("%s:%s.%s", prog.Package.Name, n.Name, fn.Name)
+		fn.Name.Value = fmt.Sprintf("%s:%s.%s", prog.Package.Name, n.baseName(), fn.Name)
 		fn.Package = n.Package
+		fn.TypeParams = n.TypeParams
+		fn.TypeArgs = n.TypeArgs
 
-		if _, found := names[fn.Name.String()]; found {
+		if _, found := names[name]; found {
 			return nil, fmt.Errorf("class '%s' has two fields/methods named '%s'", n.Name, fn.Name)
 		}
 
-		names[fn.Name.String()] = true
-		prog.RegisterFunction(fn.Name.Value, fn)
+		names[name] = true
+		prog.RegisterFunction(name, fn)
 	}
 
 	return nil, nil
 }
 
-// GenerateClassConstruction creates a function call to a class's constructor if it exists.
-func GenerateClassConstruction(name string, typ types.Type, s *Scope, c *Compiler, args []value.Value) value.Value {
-	alloc := c.CurrentBlock().NewAlloca(typ)
+// baseName returns the name of the class without any type arguments
+func (n ClassNode) baseName() string {
+	if n.genericName != "" {
+		return n.genericName
+	}
+	return n.Name
+}
 
-	load := c.CurrentBlock().NewLoad(alloc)
-	return load
+// Instantiate returns the instance of a generic class for some type arguments, declaring
+// and generating it if it doesn't exist yet. Each instance has it's own struct type and
+// method variants.
+func (n *ClassNode) Instantiate(prog *Program, args []types.Type) (*ClassNode, error) {
+	if len(n.TypeParams) == 0 || n.TypeArgs != nil {
+		return nil, fmt.Errorf("class %s is not generic and cannot take type arguments", n.Name)
+	}
+	if len(args) != len(n.TypeParams) {
+		return nil, fmt.Errorf("generic class %s expects %d type arguments. given: %d", n.Name, len(n.TypeParams), len(args))
+	}
+
+	// Instances are named with the packages of their type arguments, so that
+	// `List<a:Person>` and `List<b:Person>` are two different classes
+	argNames := make([]string, 0, len(args))
+	for _, arg := range args {
+		argNames = append(argNames, prog.qualifiedTypeName(arg))
+	}
+	name := fmt.Sprintf("%s<%s>", n.Name, strings.Join(argNames, ", "))
+
+	if inst, found := n.instances[name]; found {
+		return inst, nil
+	}
+
+	inst := &ClassNode{}
+	*inst = *n
+	inst.Name = name
+	inst.TypeArgs = args
+	inst.genericName = n.Name
+	inst.instances = nil
+	inst.constructors = nil
+
+	// The instance is cached before it's fields are generated so that
+	// it can have fields that point back to itself
+	if n.instances == nil {
+		n.instances = make(map[string]*ClassNode)
+	}
+	n.instances[name] = inst
+
+	// Fields are generated in the scope of the class's package, with the
+	// type parameters bound to the instance's type arguments
+	previousPackage := prog.Package
+	previousScope := prog.Scope
+	defer func() {
+		prog.Package = previousPackage
+		prog.Scope = previousScope
+	}()
+
+	prog.Package = n.Package
+	prog.Scope = prog.Scope.GetRoot().SpawnChild()
+	prog.Scope.PackageName = n.Package.Name
+	for i, param := range n.TypeParams {
+		prog.Scope.RegisterType(param, args[i], 0)
+	}
+
+	if _, err := inst.Declare(prog); err != nil {
+		return nil, err
+	}
+
+	found, err := prog.FindType(name)
+	if err != nil {
+		return nil, err
+	}
+	inst.typ = found.(*gtypes.StructType)
+
+	key := fmt.Sprintf("%s:%s", n.Package.Name, name)
+	if n.Package.Name == "runtime" {
+		key = name
+	}
+	prog.Classes[key] = inst
+
+	if err := inst.VerifyCorrectness(prog); err != nil {
+		return nil, err
+	}
+	if _, err := inst.Codegen(prog); err != nil {
+		return nil, err
+	}
+
+	return inst, nil
+}
+
+// qualifiedTypeName returns the name of a type as it would be written in geode,
+// including the package it is declared in
+func (p *Program) qualifiedTypeName(t types.Type) string {
+	if name, err := p.Scope.GetRoot().FindTypeName(t); err == nil {
+		return name
+	}
+	if ptr, isPtr := t.(*types.PointerType); isPtr {
+		return p.qualifiedTypeName(ptr.ElemType) + "*"
+	}
+	return t.String()
+}
+
+var typeArgPackage = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*` + separator)
+
+// shortTypeName removes the packages from the type arguments in the
+// name of a generic class instance, for showing it to the user
+func shortTypeName(name string) string {
+	return typeArgPackage.ReplaceAllString(name, "")
+}
+
+// GenerateClassConstruction returns the constructor function for a class, generating it
+// if it doesn't exist yet. The first arguments to the constructor are assigned to the
+// fields named in the class's parameters, and the rest are passed to the init method.
+func (n *ClassNode) GenerateClassConstruction(prog *Program, argTypes []types.Type) (*ir.Func, error) {
+	typeName := fmt.Sprintf("%s:%s", n.Package.Name, n.Name)
+	if n.Package.Name == "runtime" {
+		typeName = n.Name
+	}
+	found, err := prog.FindType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	structDefn := found.(*gtypes.StructType)
+
+	if len(argTypes) < len(n.Params) {
+		return nil, fmt.Errorf("class %s expects at least %d constructor arguments. given: %d", n.Name, len(n.Params), len(argTypes))
+	}
+
+	// Find the init method, which gets the remaining arguments
+	var init *ir.Func
+	initName := fmt.Sprintf("%s:%s.init", n.Package.Name, n.Name)
+	if _, exists := prog.Functions[initName]; exists {
+		initArgTypes := []types.Type{types.NewPointer(structDefn)}
+		initArgTypes = append(initArgTypes, argTypes[len(n.Params):]...)
+		init, err = prog.FindFunction([]string{initName}, initArgTypes)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(argTypes) != len(n.Params) {
+		return nil, fmt.Errorf("class %s expects %d constructor arguments. given: %d", n.Name, len(n.Params), len(argTypes))
+	}
+
+	// A class can have one constructor for each variant of it's init method
+	key := ""
+	if init != nil {
+		key = init.Name()
+	}
+	if n.constructors == nil {
+		n.constructors = make(map[string]*ir.Func)
+	}
+	if fn, found := n.constructors[key]; found {
+		return fn, nil
+	}
+
+	params := make([]*ir.Param, 0, len(argTypes))
+	paramTypes := make([]types.Type, 0, len(argTypes))
+	for _, name := range n.Params {
+		typ := structDefn.Fields[structDefn.FieldIndex(name)]
+		params = append(params, ir.NewParam(name, typ))
+		paramTypes = append(paramTypes, typ)
+	}
+	if init != nil {
+		for _, param := range init.Params[1:] {
+			params = append(params, ir.NewParam(param.Name(), param.Type()))
+			paramTypes = append(paramTypes, param.Type())
+		}
+	}
+
+	rawName := fmt.Sprintf("%s:%s.new", n.Package.Name, n.baseName())
+	fn := prog.Module.NewFunc(MangleGenericFunctionName(rawName, n.TypeArgs, paramTypes, structDefn), structDefn, params...)
+	n.constructors[key] = fn
+
+	block := fn.NewBlock(rawName + "_entry")
+
+	alloc := block.NewAlloca(structDefn)
+	block.NewStore(constant.NewZeroInitializer(structDefn), alloc)
+
+	zero := constant.NewInt(types.I32, 0)
+	for i, name := range n.Params {
+		fieldOffset := constant.NewInt(types.I32, int64(structDefn.FieldIndex(name)))
+		field := gep(alloc, zero, fieldOffset)
+		block.Insts = append(block.Insts, field)
+		block.NewStore(params[i], field)
+	}
+
+	if init != nil {
+		initArgs := []value.Value{alloc}
+		for _, param := range params[len(n.Params):] {
+			initArgs = append(initArgs, param)
+		}
+		block.NewCall(init, initArgs...)
+	}
+
+	block.NewRet(block.NewLoad(alloc))
+
+	return fn, nil
 }
 
 // NewClassInstance takes the class to generate as well as the fields
@@ -0,0 +1,207 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// ClosureNode is a function expression, example: `func(int x) int = x + n`. Variables
+// from the enclosing scope that the body uses are copied into an environment on the
+// heap when the closure is created, so the closure can outlive the function that
+// created it. Changes a closure makes to its captured variables are kept between
+// calls, but are not seen by the enclosing function.
+type ClosureNode struct {
+	NodeType
+	TokenReference
+
+	Func FunctionNode
+}
+
+// closureEnv keeps track of the variables a closure captures while its body is compiled
+type closureEnv struct {
+	parent *closureEnv
+
+	scope *Scope    // the outermost scope of the closure. Variables found above it are captured
+	block *ir.Block // the first block of the closure, where the environment is unpacked
+	env   value.Value
+	typ   *types.StructType
+
+	// pointers to the captured variables in the function that creates the closure
+	captured []value.Value
+}
+
+// NameString implements Node.NameString
+func (n ClosureNode) NameString() string { return "ClosureNode" }
+
+func (n ClosureNode) String() string {
+	s := n.Func.String()
+	return s[len("func "):]
+}
+
+// GenAccess implements Accessable.GenAccess
+func (n ClosureNode) GenAccess(prog *Program) (value.Value, error) {
+	return n.Codegen(prog)
+}
+
+// Codegen implements Node.Codegen for ClosureNode
+func (n ClosureNode) Codegen(prog *Program) (value.Value, error) {
+	params, paramTypes, err := n.Func.Arguments(prog)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := n.Func.ReturnType.GetType(prog)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := gtypes.NewClosure(ret, paramTypes...)
+
+	name := fmt.Sprintf("closure.%d", prog.closureCount)
+	prog.closureCount++
+
+	envParam := ir.NewParam("env", types.NewPointer(types.I8))
+	fn := prog.Module.NewFunc(name, ret, append([]*ir.Param{envParam}, params...)...)
+
+	c, err := n.genBody(prog, fn, envParam, params)
+	if err != nil {
+		return nil, err
+	}
+
+	block := prog.Compiler.CurrentBlock()
+
+	// Closures that don't capture anything don't need an environment
+	var env value.Value = constant.NewNull(types.NewPointer(types.I8))
+
+	if len(c.captured) > 0 {
+		// https://stackoverflow.com/a/30830445
+		elemptr := constant.NewGetElementPtr(constant.NewNull(types.NewPointer(c.typ)), constant.NewInt(types.I32, 1))
+		size := block.NewPtrToInt(elemptr, types.I32)
+
+		mem, err := prog.NewRuntimeFunctionCall("xmalloc", size)
+		if err != nil {
+			return nil, err
+		}
+		data := block.NewBitCast(mem, types.NewPointer(c.typ))
+
+		zero := constant.NewInt(types.I32, 0)
+		for i, ptr := range c.captured {
+			field := gep(data, zero, constant.NewInt(types.I32, int64(i)))
+			block.Insts = append(block.Insts, field)
+			block.NewStore(block.NewLoad(ptr), field)
+		}
+		env = mem
+	}
+
+	var closure value.Value
+	closure = block.NewInsertValue(constant.NewUndef(typ), fn, 0)
+	closure = block.NewInsertValue(closure, env, 1)
+	return closure, nil
+}
+
+// genBody generates the body of the closure into fn, returning the variables it captured
+func (n ClosureNode) genBody(prog *Program, fn *ir.Func, envParam *ir.Param, params []*ir.Param) (*closureEnv, error) {
+	c := &closureEnv{}
+	c.parent = prog.closure
+	c.typ = types.NewStruct()
+
+	prog.closure = c
+	defer func() { prog.closure = c.parent }()
+
+	// Statements in the body can leave blocks on the stack, so the compiler
+	// is restored like it is after GetFunction compiles a function
+	previousCompiler := prog.Compiler.Copy()
+	defer func() { prog.Compiler = previousCompiler }()
+	prog.Compiler.PushFunc(fn)
+	prog.Compiler.deferring = 0
+
+	prog.ScopeDown(n.Token)
+	c.scope = prog.Scope
+
+	c.block = fn.NewBlock("closure_env")
+	c.env = c.block.NewBitCast(envParam, types.NewPointer(c.typ))
+
+	for _, param := range params {
+		alloc := c.block.NewAlloca(param.Type())
+		c.block.NewStore(param, alloc)
+		prog.Scope.Add(NewVariableScopeItem(param.Name(), alloc, PrivateVisibility))
+	}
+
+	entry := fn.NewBlock("closure_entry")
+	c.block.NewBr(entry)
+	prog.Compiler.PushBlock(entry)
+
+	gen, err := n.Func.Body.Codegen(prog)
+	if err != nil {
+		return nil, err
+	}
+
+	block, ok := gen.(*ir.Block)
+	if !ok {
+		return nil, fmt.Errorf("type assertion to block in closure failed")
+	}
+
+	if block.Term == nil {
+		if !types.Equal(fn.Sig.RetType, types.Void) {
+			return nil, fmt.Errorf("closure does not end in a return statement")
+		}
+		block.NewRet(nil)
+	}
+
+	if err := prog.ScopeUp(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// capture returns the scope item a closure accesses some variable through. Variables
+// from outside the closure are added to its environment, and to the environments of
+// the closures around it.
+func (p *Program) capture(c *closureEnv, item ScopeItem, found *Scope) ScopeItem {
+	if c == nil || c.scope.Contains(found) {
+		return item
+	}
+
+	// Only local variables need to be captured, globals can be used directly
+	switch item.Value().(type) {
+	case *ir.InstAlloca, *ir.InstGetElementPtr:
+	default:
+		return item
+	}
+
+	outer := p.capture(c.parent, item, found)
+
+	index := len(c.typ.Fields)
+	c.typ.Fields = append(c.typ.Fields, outer.Value().Type().(*types.PointerType).ElemType)
+	c.captured = append(c.captured, outer.Value())
+
+	zero := constant.NewInt(types.I32, 0)
+	field := gep(c.env, zero, constant.NewInt(types.I32, int64(index)))
+	c.block.Insts = append(c.block.Insts, field)
+
+	inner := NewVariableScopeItem(item.Name(), field, PrivateVisibility)
+	c.scope.Add(inner)
+	return inner
+}
+
+// callClosure returns the function and environment needed to call the closure stored
+// at some address
+func (p *Program) callClosure(addr value.Value, closure *gtypes.ClosureType, argTypes []types.Type) (value.Value, []value.Value, error) {
+	if len(argTypes) != len(closure.Sig.Params) {
+		return nil, nil, fmt.Errorf("incorrect number of arguments passed to closure. Expected %d, given %d", len(closure.Sig.Params), len(argTypes))
+	}
+
+	block := p.Compiler.CurrentBlock()
+	val := block.NewLoad(addr)
+
+	fn := extractValue(val, 0)
+	env := extractValue(val, 1)
+	block.Insts = append(block.Insts, fn, env)
+
+	return fn, []value.Value{env}, nil
+}
@@ -21,6 +21,34 @@ type Compiler struct {
 
 	fnStack     []*ir.Func
 	fnstacklock sync.RWMutex
+
+	loops []loopBlocks
+
+	defers    []deferFrame
+	deferring int // how many deferred blocks are being generated
+}
+
+// loopBlocks are the blocks that break and continue statements jump to in a loop
+type loopBlocks struct {
+	label       string
+	fn          *ir.Func
+	continueBlk *ir.Block
+	breakBlk    *ir.Block
+
+	defers    int // the depth of the defer stack when the loop started
+	deferring int
+}
+
+// deferFrame is the code deferred in a single block, in the order it was deferred
+type deferFrame struct {
+	fn    *ir.Func
+	nodes []deferredNode
+}
+
+// deferredNode is a deferred node, and the scope it was deferred in
+type deferredNode struct {
+	node  Node
+	scope *Scope
 }
 
 // CurrentBlock -
@@ -41,6 +69,9 @@ func (c *Compiler) Copy() *Compiler {
 	n.blocks = c.blocks
 	n.fnStack = c.fnStack
 	n.typeStack = c.typeStack
+	n.loops = c.loops
+	n.defers = c.defers
+	n.deferring = c.deferring
 	return n
 }
 
@@ -144,3 +175,56 @@ func (c *Compiler) PopFunc() (fn *ir.Func) {
 func (c *Compiler) CurrentFunc() *ir.Func {
 	return c.fnStack[len(c.fnStack)-1]
 }
+
+// PushLoop appends a loop to the compiler's loop stack. The loop belongs to the current function
+func (c *Compiler) PushLoop(label string, continueBlk, breakBlk *ir.Block) {
+	c.loops = append(c.loops, loopBlocks{
+		label:       label,
+		fn:          c.CurrentFunc(),
+		continueBlk: continueBlk,
+		breakBlk:    breakBlk,
+		defers:      len(c.defers),
+		deferring:   c.deferring,
+	})
+}
+
+// PopLoop removes the innermost loop from the loop stack
+func (c *Compiler) PopLoop() {
+	if len(c.loops) >= 1 {
+		c.loops = c.loops[0 : len(c.loops)-1]
+	}
+}
+
+// FindLoop returns the innermost loop in the current function with some label. If the
+// label is empty, the innermost loop is returned.
+func (c *Compiler) FindLoop(label string) (loopBlocks, bool) {
+	fn := c.CurrentFunc()
+	for i := len(c.loops) - 1; i >= 0; i-- {
+		loop := c.loops[i]
+		if loop.fn != fn {
+			break
+		}
+		if label == "" || loop.label == label {
+			return loop, true
+		}
+	}
+	return loopBlocks{}, false
+}
+
+// PushDefers starts a new frame on the defer stack for a block in the current function
+func (c *Compiler) PushDefers() {
+	c.defers = append(c.defers, deferFrame{fn: c.CurrentFunc()})
+}
+
+// PopDefers removes the innermost frame from the defer stack
+func (c *Compiler) PopDefers() {
+	if len(c.defers) >= 1 {
+		c.defers = c.defers[0 : len(c.defers)-1]
+	}
+}
+
+// Defer adds a node to the innermost frame of the defer stack
+func (c *Compiler) Defer(node Node, scope *Scope) {
+	top := &c.defers[len(c.defers)-1]
+	top.nodes = append(top.nodes, deferredNode{node, scope})
+}
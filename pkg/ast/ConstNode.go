@@ -0,0 +1,498 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// ConstNode is a constant declaration, example: `const SIZE = 64 * 1024`. The value is
+// folded when the program is compiled with arbitrary precision, and is given a type
+// where it is used, like a literal would be. Constants declared with a type, like
+// `const byte MASK = 0x7f`, always have that type. Other packages reference
+// constants through the package, like `mem:PAGE_SIZE`
+type ConstNode struct {
+	NodeType
+	TokenReference
+
+	Name  string
+	Type  *TypeNode
+	Value Node
+}
+
+// NameString implements Node.NameString
+func (n ConstNode) NameString() string { return "ConstNode" }
+
+func (n ConstNode) String() string {
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "const ")
+	if n.Type != nil {
+		fmt.Fprintf(buff, "%s ", n.Type)
+	}
+	fmt.Fprintf(buff, "%s = %s", n.Name, n.Value)
+	return buff.String()
+}
+
+// Codegen implements Node.Codegen for ConstNode. Constants don't generate
+// anything, they are folded into the places they are used
+func (n ConstNode) Codegen(prog *Program) (value.Value, error) {
+	return nil, nil
+}
+
+// constDecl is a constant declared in a package, and the value it folds to
+type constDecl struct {
+	node    ConstNode
+	pkg     *Package
+	value   *constValue
+	folding bool
+}
+
+type constKind int
+
+const (
+	constInt constKind = iota
+	constChar
+	constFloat
+	constString
+	constBool
+)
+
+// The precision floating point constants are folded with
+const constFloatPrec = 256
+
+// constValue is the value of a constant expression
+type constValue struct {
+	kind constKind
+	i    *big.Int
+	f    *big.Float
+	s    string
+	b    bool
+
+	// The type the constant was declared with, or nil if it takes the type of
+	// where it is used
+	typ types.Type
+}
+
+func (c *constValue) String() string {
+	switch c.kind {
+	case constInt, constChar:
+		return c.i.String()
+	case constFloat:
+		return c.f.Text('g', 10)
+	case constString:
+		return fmt.Sprintf("%q", c.s)
+	}
+	return fmt.Sprintf("%t", c.b)
+}
+
+func (c *constValue) isInt() bool {
+	return c.kind == constInt || c.kind == constChar
+}
+
+// float returns the value of a numeric constant as a float
+func (c *constValue) float() *big.Float {
+	if c.kind == constFloat {
+		return c.f
+	}
+	return new(big.Float).SetPrec(constFloatPrec).SetInt(c.i)
+}
+
+func newConstInt(i *big.Int) *constValue {
+	return &constValue{kind: constInt, i: i}
+}
+
+func newConstFloat(f *big.Float) *constValue {
+	return &constValue{kind: constFloat, f: f}
+}
+
+func newConstBool(b bool) *constValue {
+	return &constValue{kind: constBool, b: b}
+}
+
+// lookupConstant returns the value of the constant with some name, as seen from
+// the current package. If there is no constant with that name, nil is returned
+func (p *Program) lookupConstant(name string) (*constValue, error) {
+	return p.findConstant(name, p.Package.Name)
+}
+
+func (p *Program) findConstant(name string, pkg string) (*constValue, error) {
+	ns, _ := ParseName(name)
+	paths := []string{name}
+	if ns == "" {
+		paths = []string{fmt.Sprintf("%s:%s", pkg, name), name}
+	}
+
+	for _, path := range paths {
+		if decl, ok := p.constants[path]; ok {
+			return p.foldConstDecl(decl)
+		}
+	}
+	return nil, nil
+}
+
+// foldConstDecl folds the value of a constant the first time it is needed
+func (p *Program) foldConstDecl(decl *constDecl) (*constValue, error) {
+	if decl.value != nil {
+		return decl.value, nil
+	}
+	n := decl.node
+	if decl.folding {
+		return nil, n.Errorf("constant %s refers to itself", n.Name)
+	}
+	decl.folding = true
+	defer func() { decl.folding = false }()
+
+	val, err := p.foldConst(n.Value, decl.pkg.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Type != nil {
+		typ, err := n.Type.GetType(p)
+		if err != nil {
+			return nil, n.Locate(err)
+		}
+		val, err = p.convertConst(val, typ)
+		if err != nil {
+			return nil, n.Errorf("constant %s: %s", n.Name, err)
+		}
+	}
+
+	decl.value = val
+	return val, nil
+}
+
+// foldConst evaluates a constant expression in some package
+func (p *Program) foldConst(n Node, pkg string) (*constValue, error) {
+	switch n := n.(type) {
+	case IntNode:
+		return newConstInt(big.NewInt(n.Value)), nil
+
+	case CharNode:
+		return &constValue{kind: constChar, i: big.NewInt(int64(n.Value))}, nil
+
+	case FloatNode:
+		return newConstFloat(new(big.Float).SetPrec(constFloatPrec).SetFloat64(n.Value)), nil
+
+	case StringNode:
+		return &constValue{kind: constString, s: n.Value}, nil
+
+	case BooleanNode:
+		return newConstBool(n.Value == "true"), nil
+
+	case IdentNode:
+		val, err := p.findConstant(n.Value, pkg)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, n.Errorf("%s is not a constant", n.Value)
+		}
+		return val, nil
+
+	case UnaryNode:
+		val, err := p.foldConst(n.Operand, pkg)
+		if err != nil {
+			return nil, err
+		}
+		res, err := foldConstUnary(n.Operator, val)
+		if err != nil {
+			return nil, n.Locate(err)
+		}
+		return res, nil
+
+	case BinaryNode:
+		l, err := p.foldConst(n.Left, pkg)
+		if err != nil {
+			return nil, err
+		}
+		r, err := p.foldConst(n.Right, pkg)
+		if err != nil {
+			return nil, err
+		}
+		res, err := p.foldConstBinary(n.OP, l, r)
+		if err != nil {
+			return nil, n.Locate(err)
+		}
+		return res, nil
+
+	case CastNode:
+		val, err := p.foldConst(n.Source, pkg)
+		if err != nil {
+			return nil, err
+		}
+		typ, err := n.Type.GetType(p)
+		if err != nil {
+			return nil, n.Locate(err)
+		}
+		res, err := p.convertConst(val, typ)
+		if err != nil {
+			return nil, n.Locate(err)
+		}
+		return res, nil
+	}
+
+	return nil, n.Errorf("%s is not a constant expression", n)
+}
+
+func foldConstUnary(op string, val *constValue) (*constValue, error) {
+	switch {
+	case op == "-" && val.isInt():
+		return &constValue{kind: val.kind, i: new(big.Int).Neg(val.i), typ: val.typ}, nil
+	case op == "-" && val.kind == constFloat:
+		return &constValue{kind: constFloat, f: new(big.Float).Neg(val.f), typ: val.typ}, nil
+	case op == "!" && val.kind == constBool:
+		return newConstBool(!val.b), nil
+	}
+	return nil, fmt.Errorf("invalid operation %s on constant %s", op, val)
+}
+
+func (p *Program) foldConstBinary(op string, l, r *constValue) (*constValue, error) {
+	invalid := fmt.Errorf("invalid operation %s %s %s on constants", l, op, r)
+
+	// Typed constants keep their type through operations with untyped ones
+	typ := l.typ
+	if typ == nil {
+		typ = r.typ
+	} else if r.typ != nil && !types.Equal(l.typ, r.typ) {
+		return nil, fmt.Errorf("mismatched types %s and %s in constant %s %s %s", p.typeInfoName(l.typ), p.typeInfoName(r.typ), l, op, r)
+	}
+
+	switch {
+	case l.kind == constString && r.kind == constString:
+		switch op {
+		case "+":
+			return &constValue{kind: constString, s: l.s + r.s}, nil
+		case "==":
+			return newConstBool(l.s == r.s), nil
+		case "!=":
+			return newConstBool(l.s != r.s), nil
+		}
+		return nil, invalid
+
+	case l.kind == constBool && r.kind == constBool:
+		switch op {
+		case "&&":
+			return newConstBool(l.b && r.b), nil
+		case "||":
+			return newConstBool(l.b || r.b), nil
+		case "==":
+			return newConstBool(l.b == r.b), nil
+		case "!=":
+			return newConstBool(l.b != r.b), nil
+		}
+		return nil, invalid
+
+	case l.isInt() && r.isInt():
+		res, err := foldConstInt(op, l.i, r.i)
+		if err != nil || res.kind == constBool {
+			return res, err
+		}
+		// Operations on two chars are still chars
+		if l.kind == constChar && r.kind == constChar {
+			res.kind = constChar
+		}
+		res.typ = typ
+		if typ != nil {
+			return p.convertConst(res, typ)
+		}
+		return res, nil
+
+	case (l.isInt() || l.kind == constFloat) && (r.isInt() || r.kind == constFloat):
+		res, err := foldConstFloat(op, l.float(), r.float())
+		if err != nil || res.kind == constBool {
+			return res, err
+		}
+		res.typ = typ
+		return res, nil
+	}
+	return nil, invalid
+}
+
+// The largest shift folded, so constants can't use up all the memory
+const maxConstShift = 1024
+
+func foldConstInt(op string, l, r *big.Int) (*constValue, error) {
+	res := new(big.Int)
+	switch op {
+	case "+":
+		res.Add(l, r)
+	case "-":
+		res.Sub(l, r)
+	case "*":
+		res.Mul(l, r)
+	case "/", "%":
+		if r.Sign() == 0 {
+			return nil, fmt.Errorf("division by zero in constant %s %s %s", l, op, r)
+		}
+		if op == "/" {
+			res.Quo(l, r)
+		} else {
+			res.Rem(l, r)
+		}
+	case "<<", ">>":
+		if r.Sign() < 0 || r.Cmp(big.NewInt(maxConstShift)) > 0 {
+			return nil, fmt.Errorf("invalid shift count %s in constant %s %s %s", r, l, op, r)
+		}
+		if op == "<<" {
+			res.Lsh(l, uint(r.Int64()))
+		} else {
+			res.Rsh(l, uint(r.Int64()))
+		}
+	case "^":
+		res.Xor(l, r)
+	case "==", "!=", "<", "<=", ">", ">=":
+		return newConstBool(compareConst(op, l.Cmp(r))), nil
+	default:
+		return nil, fmt.Errorf("invalid operation %s %s %s on constants", l, op, r)
+	}
+	return newConstInt(res), nil
+}
+
+func foldConstFloat(op string, l, r *big.Float) (*constValue, error) {
+	res := new(big.Float).SetPrec(constFloatPrec)
+	switch op {
+	case "+":
+		res.Add(l, r)
+	case "-":
+		res.Sub(l, r)
+	case "*":
+		res.Mul(l, r)
+	case "/":
+		if r.Sign() == 0 {
+			return nil, fmt.Errorf("division by zero in constant %s %s %s", l.Text('g', 10), op, r.Text('g', 10))
+		}
+		res.Quo(l, r)
+	case "==", "!=", "<", "<=", ">", ">=":
+		return newConstBool(compareConst(op, l.Cmp(r))), nil
+	default:
+		return nil, fmt.Errorf("invalid operation %s %s %s on constants", l.Text('g', 10), op, r.Text('g', 10))
+	}
+	return newConstFloat(res), nil
+}
+
+// compareConst returns the result of a comparison, given how the operands compare
+func compareConst(op string, cmp int) bool {
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+	return cmp >= 0
+}
+
+// convertConst gives a constant a type, and makes sure the value fits in it
+func (p *Program) convertConst(val *constValue, typ types.Type) (*constValue, error) {
+	res := *val
+	res.typ = typ
+
+	switch t := typ.(type) {
+	case *types.IntType:
+		if t.BitSize == 1 {
+			if val.kind != constBool {
+				break
+			}
+			return &res, nil
+		}
+		if val.kind == constFloat {
+			i, acc := val.f.Int(nil)
+			if acc != big.Exact {
+				return nil, fmt.Errorf("constant %s is truncated when converted to an integer", val)
+			}
+			res.kind = constInt
+			res.i = i
+		}
+		if !res.isInt() {
+			break
+		}
+		if !constFits(res.i, t) {
+			return nil, fmt.Errorf("constant %s overflows %s", res.i, p.typeInfoName(t))
+		}
+		return &res, nil
+
+	case *types.FloatType:
+		if !val.isInt() && val.kind != constFloat {
+			break
+		}
+		res.kind = constFloat
+		res.f = val.float()
+		return &res, nil
+
+	case *types.PointerType:
+		if val.kind == constString && types.Equal(t, types.NewPointer(types.I8)) {
+			return &res, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot convert constant %s to %s", val, p.typeInfoName(typ))
+}
+
+// constFits reports if an integer constant can be stored in an integer type. Like
+// integer literals, values can use the range of either the signed or unsigned type
+func constFits(i *big.Int, t *types.IntType) bool {
+	min := new(big.Int).Lsh(big.NewInt(-1), uint(t.BitSize-1))
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(t.BitSize)), big.NewInt(1))
+	return i.Cmp(min) >= 0 && i.Cmp(max) <= 0
+}
+
+// constantValue generates the value of a constant, typed the way a literal of the
+// same value would be unless the constant was declared with a type
+func (p *Program) constantValue(c *constValue) (value.Value, error) {
+	typ := c.typ
+
+	switch c.kind {
+	case constInt, constChar:
+		if typ == nil {
+			typ = types.I64
+			if c.kind == constChar {
+				typ = types.I8
+			}
+			if !constFits(c.i, typ.(*types.IntType)) {
+				return nil, fmt.Errorf("constant %s overflows %s", c.i, p.typeInfoName(typ))
+			}
+		}
+		// Values past the signed range are stored with the same bits
+		bits := new(big.Int).And(c.i, new(big.Int).SetUint64(math.MaxUint64))
+		return constant.NewInt(typ.(*types.IntType), int64(bits.Uint64())), nil
+
+	case constFloat:
+		if typ == nil {
+			typ = types.Double
+		}
+		f, _ := c.f.Float64()
+		return constant.NewFloat(typ.(*types.FloatType), f), nil
+
+	case constString:
+		str := StringNode{}
+		str.NodeType = nodeString
+		str.Value = c.s
+		return str.Codegen(p)
+	}
+	return constant.NewBool(c.b), nil
+}
+
+// arrayTypeLength returns the length of a fixed size array type, like `byte[SIZE]`
+func (p *Program) arrayTypeLength(n Node) (uint64, error) {
+	val, err := p.foldConst(n, p.Package.Name)
+	if err != nil {
+		return 0, err
+	}
+	if !val.isInt() {
+		return 0, n.Errorf("array length %s must be an integer, not %s", n, val)
+	}
+	if val.i.Sign() <= 0 || !constFits(val.i, types.I32) {
+		return 0, n.Errorf("invalid array length %s (%s)", n, val.i)
+	}
+	return val.i.Uint64(), nil
+}
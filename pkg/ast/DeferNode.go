@@ -0,0 +1,67 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/value"
+)
+
+// DeferNode schedules an expression or a block to run when the block it is in
+// ends, example: `defer free(buf);`. Deferred code runs in the reverse order it
+// was deferred, whether the block ends normally or through a return, break or
+// continue. It sees the variables of the block as they are when it runs.
+type DeferNode struct {
+	NodeType
+	TokenReference
+
+	Body Node
+}
+
+// NameString implements Node.NameString
+func (n DeferNode) NameString() string { return "DeferNode" }
+
+func (n DeferNode) String() string {
+	return fmt.Sprintf("defer %s", n.Body)
+}
+
+// Codegen implements Node.Codegen for DeferNode
+func (n DeferNode) Codegen(prog *Program) (value.Value, error) {
+	if len(prog.Compiler.defers) == 0 {
+		return nil, n.Errorf("defer statement outside of a block")
+	}
+	prog.Compiler.Defer(n.Body, prog.Scope)
+	return nil, nil
+}
+
+// runDefers generates the code deferred in the current function's blocks, from
+// the innermost block out to the given depth of the defer stack
+func (p *Program) runDefers(depth int) error {
+	fn := p.Compiler.CurrentFunc()
+	scope := p.Scope
+	all := p.Compiler.defers
+	defer func() {
+		p.Scope = scope
+		p.Compiler.defers = all
+	}()
+
+	for i := len(all) - 1; i >= depth; i-- {
+		frame := all[i]
+		if frame.fn != fn {
+			break
+		}
+
+		// Deferred code only sees the blocks outside of its own, so a block in it
+		// can't run the code that was deferred alongside it again
+		p.Compiler.defers = append([]deferFrame{}, all[:i]...)
+		for j := len(frame.nodes) - 1; j >= 0; j-- {
+			p.Scope = frame.nodes[j].scope
+			p.Compiler.deferring++
+			_, err := frame.nodes[j].node.Codegen(p)
+			p.Compiler.deferring--
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
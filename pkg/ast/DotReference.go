@@ -50,10 +50,23 @@ func (n DotReference) BaseAddr(prog *Program) value.Value {
 }
 
 // GetFunc implements Callable.GetFunc
-func (n DotReference) GetFunc(prog *Program, argTypes []types.Type) (*ir.Func, []value.Value, error) {
+func (n DotReference) GetFunc(prog *Program, argTypes []types.Type) (value.Value, []value.Value, error) {
 
 	class := n.BaseType(prog)
 
+	if proto, isProto := class.(*gtypes.ProtocolType); isProto {
+		return n.getProtocolFunc(prog, proto, argTypes)
+	}
+
+	// Fields holding a closure are called through it
+	if structType, isStruct := class.(*gtypes.StructType); isStruct {
+		if index := structType.FieldIndex(n.Field.String()); index != -1 {
+			if closure, isClosure := structType.Fields[index].(*gtypes.ClosureType); isClosure {
+				return prog.callClosure(n.Alloca(prog), closure, argTypes)
+			}
+		}
+	}
+
 	name, err := prog.Scope.FindTypeName(class)
 	if err != nil {
 		return nil, nil, err
@@ -81,13 +94,50 @@ func (n DotReference) GetFunc(prog *Program, argTypes []types.Type) (*ir.Func, [
 	// for k := range prog.Functions {
 	// 	fmt.Println(k)
 	// }
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fn, args, nil
+}
+
+// getProtocolFunc looks up a method in the vtable of a protocol value. The
+// data pointer of the value is passed as the first argument to the method.
+func (n DotReference) getProtocolFunc(prog *Program, proto *gtypes.ProtocolType, argTypes []types.Type) (value.Value, []value.Value, error) {
+	fieldName := n.Field.String()
+
+	index := proto.MethodIndex(fieldName)
+	if index == -1 {
+		name, _ := prog.Scope.FindTypeName(proto)
+		return nil, nil, fmt.Errorf("protocol %s has no method %q", name, fieldName)
+	}
+
+	sig := proto.MethodType(index)
+	if len(sig.Params)-1 != len(argTypes) {
+		return nil, nil, fmt.Errorf("incorrect number of arguments passed to method %q. Expected %d, given %d", fieldName, len(sig.Params)-1, len(argTypes))
+	}
+
+	block := prog.Compiler.CurrentBlock()
 
-	return fn, args, err
+	val := block.NewLoad(n.BaseAddr(prog))
+
+	data := extractValue(val, 0)
+	vtable := extractValue(val, 1)
+	block.Insts = append(block.Insts, data, vtable)
+
+	zero := constant.NewInt(types.I32, 0)
+	slot := gep(vtable, zero, constant.NewInt(types.I32, int64(index)))
+	block.Insts = append(block.Insts, slot)
+
+	return block.NewLoad(slot), []value.Value{data}, nil
 }
 
 // Alloca returns the nearest alloca instruction in this scope with the given name
 func (n DotReference) Alloca(prog *Program) value.Value {
 	base := n.Base.Alloca(prog)
+	if ident, isIdent := n.Base.(IdentNode); isIdent {
+		prog.useName(ident.Token, base)
+	}
 	index := 0
 	baseType := n.BaseType(prog)
 
@@ -0,0 +1,199 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// EnumNode is an enum declaration, example: `enum Shape { Circle(float r), Rect(float w, float h) }`.
+// Variants are referenced through the name of the enum, like `Shape:Circle(1.0)`
+type EnumNode struct {
+	NodeType
+	TokenReference
+
+	Package  *Package
+	Name     string
+	Variants []EnumVariantNode
+}
+
+// EnumVariantNode is a single variant of an enum, and the fields it carries
+type EnumVariantNode struct {
+	TokenReference
+
+	Name   string
+	Fields []string
+	Types  []TypeNode
+}
+
+// NameString implements Node.NameString
+func (n EnumNode) NameString() string { return "EnumNode" }
+
+func (n EnumNode) String() string {
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "enum %s {", n.Name)
+	for i, v := range n.Variants {
+		fmt.Fprintf(buff, " %s", v)
+		if i < len(n.Variants)-1 {
+			fmt.Fprintf(buff, ",")
+		}
+	}
+	fmt.Fprintf(buff, " }")
+	return buff.String()
+}
+
+func (v EnumVariantNode) String() string {
+	if len(v.Fields) == 0 {
+		return v.Name
+	}
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "%s(", v.Name)
+	for i, f := range v.Fields {
+		fmt.Fprintf(buff, "%s %s", v.Types[i], f)
+		if i < len(v.Fields)-1 {
+			fmt.Fprintf(buff, ", ")
+		}
+	}
+	fmt.Fprintf(buff, ")")
+	return buff.String()
+}
+
+// Declare an enum type. The payload is laid out by Codegen, once every
+// type a field could reference has been declared.
+func (n EnumNode) Declare(prog *Program) (value.Value, error) {
+	if len(n.Variants) == 0 {
+		return nil, n.Errorf("enum '%s' must have at least one variant", n.Name)
+	}
+
+	seen := map[string]bool{}
+	for _, v := range n.Variants {
+		if seen[v.Name] {
+			return nil, v.Errorf("enum '%s' has two variants named '%s'", n.Name, v.Name)
+		}
+		seen[v.Name] = true
+	}
+
+	// The type is named with the package, like classes, so enums with the
+	// same name in two packages are different types
+	enum := gtypes.NewEnum()
+	prog.Module.NewTypeDef(fmt.Sprintf("enum.%s:%s", prog.Scope.PackageName, n.Name), enum)
+
+	scopeName := n.Name
+	if prog.Package.Name != "runtime" {
+		scopeName = fmt.Sprintf("%s:%s", prog.Scope.PackageName, n.Name)
+	}
+	prog.Scope.GetRoot().RegisterType(scopeName, enum, -1)
+
+	return nil, nil
+}
+
+// Codegen implements Node.Codegen for EnumNode
+func (n EnumNode) Codegen(prog *Program) (value.Value, error) {
+	found, err := prog.FindType(n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	enum := found.(*gtypes.EnumType)
+
+	variants := make([]*gtypes.EnumVariant, 0, len(n.Variants))
+	for _, v := range n.Variants {
+		fields := make([]types.Type, 0, len(v.Types))
+		for _, t := range v.Types {
+			ty, err := t.GetType(prog)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, ty)
+		}
+		variants = append(variants, &gtypes.EnumVariant{
+			Name:    v.Name,
+			Fields:  v.Fields,
+			Payload: types.NewStruct(fields...),
+		})
+	}
+	enum.SetVariants(variants...)
+
+	return nil, nil
+}
+
+// FindEnumVariant returns the enum and tag of a variant referenced like `Color:Red`.
+// If the name doesn't refer to an enum, the returned enum is nil.
+func (p *Program) FindEnumVariant(name string) (*gtypes.EnumType, int, error) {
+	ns, nm := ParseName(name)
+	if ns == "" {
+		return nil, -1, nil
+	}
+
+	found, err := p.FindType(ns)
+	if err != nil {
+		return nil, -1, nil
+	}
+
+	enum, isEnum := found.(*gtypes.EnumType)
+	if !isEnum {
+		return nil, -1, nil
+	}
+
+	tag := enum.VariantIndex(nm)
+	if tag == -1 {
+		return nil, -1, fmt.Errorf("enum %s has no variant named %s", ns, nm)
+	}
+	return enum, tag, nil
+}
+
+// NewEnumValue returns the value of a variant that carries no fields
+func (p *Program) NewEnumValue(enum *gtypes.EnumType, tag int) (value.Value, error) {
+	variant := enum.Variants[tag]
+	if len(variant.Fields) > 0 {
+		return nil, fmt.Errorf("enum variant %s:%s must be given its %d fields", p.typeInfoName(enum), variant.Name, len(variant.Fields))
+	}
+	tagValue := constant.NewInt(types.I32, int64(tag))
+	return p.Compiler.CurrentBlock().NewInsertValue(constant.NewZeroInitializer(enum), tagValue, 0), nil
+}
+
+// EnumConstructor returns a function that takes the fields of an enum variant
+// and returns a value of that variant. It is named after the package-qualified
+// name of the enum's type, like `enum.main:Shape.Circle`
+func (p *Program) EnumConstructor(enum *gtypes.EnumType, tag int) *ir.Func {
+	variant := enum.Variants[tag]
+	name := fmt.Sprintf("%s.%s", enum.TypeName, variant.Name)
+	if fn, ok := p.enumConstructors[name]; ok {
+		return fn
+	}
+
+	params := make([]*ir.Param, 0, len(variant.Fields))
+	for i, field := range variant.Fields {
+		params = append(params, ir.NewParam(field, variant.Payload.Fields[i]))
+	}
+	fn := p.Module.NewFunc(name, enum, params...)
+	p.enumConstructors[name] = fn
+
+	blk := fn.NewBlock("entry")
+	alloc := blk.NewAlloca(enum)
+
+	tagPtr := gep(alloc, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
+	blk.Insts = append(blk.Insts, tagPtr)
+	blk.NewStore(constant.NewInt(types.I32, int64(tag)), tagPtr)
+
+	payload := enumPayload(blk, alloc, variant)
+	for i, param := range params {
+		field := blk.NewGetElementPtr(payload, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, int64(i)))
+		blk.NewStore(param, field)
+	}
+
+	blk.NewRet(blk.NewLoad(alloc))
+	return fn
+}
+
+// enumPayload returns a pointer to the fields of a variant, given a pointer to an enum value
+func enumPayload(blk *ir.Block, ptr value.Value, variant *gtypes.EnumVariant) value.Value {
+	payload := gep(ptr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 1))
+	blk.Insts = append(blk.Insts, payload)
+	return blk.NewBitCast(payload, types.NewPointer(variant.Payload))
+}
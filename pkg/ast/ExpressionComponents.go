@@ -85,7 +85,8 @@ func (c *BaseComponent) ConstructNode(prev Node) (Node, error) {
 type IdentComponent struct {
 	componentChainNode
 
-	Value string
+	Value    string
+	Generics []TypeNode
 }
 
 // Ident implements ExpComponent.Ident
@@ -97,6 +98,7 @@ func (c *IdentComponent) Ident() string {
 func (c *IdentComponent) ConstructNode(prev Node) (Node, error) {
 	n := NewIdentNode(c.Value)
 	n.Token = c.token
+	n.Generics = c.Generics
 	return n, nil
 }
 
@@ -204,6 +206,19 @@ func (c *NumberComponent) ConstructNode(prev Node) (Node, error) {
 	if n == nil {
 		return nil, fmt.Errorf("unable to get number type from number component's value")
 	}
+
+	// Errors about the number are reported at it's token
+	switch num := n.(type) {
+	case IntNode:
+		num.Token = c.token
+		return num, nil
+	case FloatNode:
+		num.Token = c.token
+		return num, nil
+	case CharNode:
+		num.Token = c.token
+		return num, nil
+	}
 	return n, nil
 }
 
@@ -247,6 +262,52 @@ func (c *SubscriptComponent) Ident() string {
 	return "[" + c.Value.String() + "]"
 }
 
+// =========================== SliceComponent ===========================
+
+// SliceComponent is an expression component for slice expressions, like `a[1:3]`
+type SliceComponent struct {
+	componentChainNode
+
+	Low  Node
+	High Node
+}
+
+// ConstructNode returns the ast node for the expression component
+func (c *SliceComponent) ConstructNode(prev Node) (Node, error) {
+	n := SliceNode{}
+	n.Token = c.token
+	n.NodeType = nodeSlice
+	var ok bool
+	n.Source, ok = prev.(Accessable)
+	if !ok {
+		return nil, fmt.Errorf("previous node in SliceComponent is not accessable: %T", prev)
+	}
+
+	if c.Low != nil {
+		if n.Low, ok = c.Low.(Accessable); !ok {
+			return nil, fmt.Errorf("lower bound in SliceComponent is not accessable: %T", c.Low)
+		}
+	}
+	if c.High != nil {
+		if n.High, ok = c.High.(Accessable); !ok {
+			return nil, fmt.Errorf("upper bound in SliceComponent is not accessable: %T", c.High)
+		}
+	}
+	return n, nil
+}
+
+// Ident implements ExpComponent.Ident
+func (c *SliceComponent) Ident() string {
+	low, high := "", ""
+	if c.Low != nil {
+		low = c.Low.String()
+	}
+	if c.High != nil {
+		high = c.High.String()
+	}
+	return "[" + low + ":" + high + "]"
+}
+
 // =========================== ArrayComponent ===========================
 
 // ArrayComponent is an expression component for numbers
@@ -389,6 +450,28 @@ func (c *BooleanComponent) ConstructNode(prev Node) (Node, error) {
 	return n, nil
 }
 
+// =========================== NilComponent ===========================
+
+// NilComponent is an expression component for nil
+type NilComponent struct {
+	componentChainNode
+}
+
+// Ident implements ExpComponent.Ident
+func (c *NilComponent) Ident() string {
+	return "nil"
+}
+
+// ConstructNode returns the ast node for the expression component
+func (c *NilComponent) ConstructNode(prev Node) (Node, error) {
+
+	n := NilNode{}
+	n.Token = c.token
+	n.NodeType = nodeNil
+
+	return n, nil
+}
+
 // =========================== CharComponent ===========================
 
 // CharComponent is an expression component for numbers
@@ -438,3 +521,27 @@ func (c *TypeInfoComponent) ConstructNode(prev Node) (Node, error) {
 	n.T = c.Type
 	return n, nil
 }
+
+// =========================== ClosureComponent ===========================
+
+// ClosureComponent is an expression component for closure expressions
+type ClosureComponent struct {
+	componentChainNode
+
+	Func FunctionNode
+}
+
+// Ident implements ExpComponent.Ident
+func (c *ClosureComponent) Ident() string {
+	node, _ := c.ConstructNode(nil)
+	return fmt.Sprintf("%s", node)
+}
+
+// ConstructNode returns the ast node for the expression component
+func (c *ClosureComponent) ConstructNode(prev Node) (Node, error) {
+	n := ClosureNode{}
+	n.Token = c.token
+	n.NodeType = nodeClosure
+	n.Func = c.Func
+	return n, nil
+}
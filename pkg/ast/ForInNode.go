@@ -0,0 +1,250 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// ForInNode is a loop over the elements of a collection, example: `for i, x in nums { ... }`.
+// Slices, arrays and strings are looped over by index. Any other value is looped over
+// through it's `has_next` and `next` methods, which are called until `has_next` is false
+type ForInNode struct {
+	NodeType
+	TokenReference
+
+	Index      int
+	Key        string // The name the index is bound to, if one was given
+	Value      string
+	Collection Node
+	Body       Node
+	Label      string
+}
+
+func (n ForInNode) String() string {
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "for ")
+	if n.Key != "" {
+		fmt.Fprintf(buff, "%s, ", n.Key)
+	}
+	fmt.Fprintf(buff, "%s in %s %s", n.Value, n.Collection, n.Body)
+	return buff.String()
+}
+
+// NameString implements Node.NameString
+func (n ForInNode) NameString() string { return "ForInNode" }
+
+// forInSource generates the parts of a for-in loop that depend on what is
+// being looped over
+type forInSource struct {
+	// cond returns whether there is an element at the index
+	cond func(index value.Value) (value.Value, error)
+	// elem returns the element at the index
+	elem func(index value.Value) (value.Value, error)
+}
+
+// Codegen implements Node.Codegen for ForInNode
+func (n ForInNode) Codegen(prog *Program) (value.Value, error) {
+
+	// The name of the blocks is prefixed so we can determine which loop a block is for.
+	namePrefix := fmt.Sprintf("FI%X_", n.Index)
+	parentBlock := prog.Compiler.CurrentBlock()
+	parentFunc := parentBlock.Parent
+
+	prog.ScopeDown(n.Token)
+
+	source, err := n.source(prog)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := createBlockAlloca(parentFunc, types.I64, "")
+	prog.Compiler.CurrentBlock().NewStore(constant.NewInt(types.I64, 0), counter)
+
+	condBlk := parentFunc.NewBlock(mangleName(namePrefix + "cond"))
+	bodyBlk := parentFunc.NewBlock(mangleName(namePrefix + "body"))
+	stepBlk := parentFunc.NewBlock(mangleName(namePrefix + "step"))
+	endBlk := parentFunc.NewBlock(mangleName(namePrefix + "end"))
+	prog.Compiler.CurrentBlock().NewBr(condBlk)
+
+	err = prog.Compiler.genInBlock(condBlk, func() error {
+		cond, err := source.cond(condBlk.NewLoad(counter))
+		if err != nil {
+			return err
+		}
+		cond, err = createTypeCast(prog, cond, types.I1)
+		if err != nil {
+			return err
+		}
+		// The condition might have made blocks of it's own
+		prog.Compiler.CurrentBlock().NewCondBr(cond, bodyBlk, endBlk)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prog.Compiler.PushLoop(n.Label, stepBlk, endBlk)
+	err = prog.Compiler.genInBlock(bodyBlk, func() error {
+		index := bodyBlk.NewLoad(counter)
+		if err := n.bind(prog, n.Key, index); err != nil {
+			return err
+		}
+		elem, err := source.elem(index)
+		if err != nil {
+			return err
+		}
+		if err := n.bind(prog, n.Value, elem); err != nil {
+			return err
+		}
+
+		scp := prog.Scope
+		gen, err := n.Body.Codegen(prog)
+		if err != nil {
+			return err
+		}
+		prog.Scope = scp
+		BranchIfNoTerminator(gen.(*ir.Block), stepBlk)
+		BranchIfNoTerminator(bodyBlk, stepBlk)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	prog.Compiler.PopLoop()
+
+	next := stepBlk.NewAdd(stepBlk.NewLoad(counter), constant.NewInt(types.I64, 1))
+	stepBlk.NewStore(next, counter)
+	stepBlk.NewBr(condBlk)
+
+	prog.Compiler.PushBlock(endBlk)
+	if err := prog.ScopeUp(); err != nil {
+		return nil, err
+	}
+	return endBlk, nil
+}
+
+// bind stores a value in a new variable for the body of the loop. Values
+// bound to `_` are skipped
+func (n ForInNode) bind(prog *Program, name string, val value.Value) error {
+	if name == "" || name == "_" {
+		return nil
+	}
+	blk := prog.Compiler.CurrentBlock()
+	alloca := createBlockAlloca(blk.Parent, val.Type(), name)
+	blk.NewStore(val, alloca)
+	prog.Scope.Add(NewVariableScopeItem(name, alloca, PrivateVisibility))
+	return nil
+}
+
+// source decides how the collection is looped over
+func (n ForInNode) source(prog *Program) (forInSource, error) {
+	coll, err := n.Collection.Codegen(prog)
+	if err != nil {
+		return forInSource{}, err
+	}
+
+	typ := coll.Type()
+
+	if gtypes.IsSlice(typ) {
+		ptr, length, _ := sliceFields(prog.Compiler.CurrentBlock(), coll)
+		return indexedSource(prog, ptr, func(index value.Value) (value.Value, error) {
+			return prog.Compiler.CurrentBlock().NewICmp(enum.IPredSLT, index, length), nil
+		}), nil
+	}
+
+	// Fixed size arrays are copied so their elements can be indexed
+	if array, isArray := typ.(*types.ArrayType); isArray {
+		blk := prog.Compiler.CurrentBlock()
+		tmp := createBlockAlloca(blk.Parent, array, "array")
+		blk.NewStore(coll, tmp)
+		zero := constant.NewInt(types.I64, 0)
+		ptr := gep(tmp, zero, zero)
+		blk.Insts = append(blk.Insts, ptr)
+		length := constant.NewInt(types.I64, int64(array.Len))
+		return indexedSource(prog, ptr, func(index value.Value) (value.Value, error) {
+			return prog.Compiler.CurrentBlock().NewICmp(enum.IPredSLT, index, length), nil
+		}), nil
+	}
+
+	// Strings are looped over until their terminating null byte
+	if types.Equal(typ, types.NewPointer(types.I8)) {
+		var src forInSource
+		src = indexedSource(prog, coll, func(index value.Value) (value.Value, error) {
+			c, err := src.elem(index)
+			if err != nil {
+				return nil, err
+			}
+			return prog.Compiler.CurrentBlock().NewICmp(enum.IPredNE, c, constant.NewInt(types.I8, 0)), nil
+		})
+		return src, nil
+	}
+
+	// Arrays can only be looped over if we know how long they are
+	if ident, isIdent := n.Collection.(IdentNode); isIdent && types.IsPointer(typ) {
+		if length, ok := prog.knownArrayLength(ident); ok {
+			return indexedSource(prog, coll, func(index value.Value) (value.Value, error) {
+				return prog.Compiler.CurrentBlock().NewICmp(enum.IPredSLT, index, constant.NewInt(types.I64, length)), nil
+			}), nil
+		}
+	}
+
+	return n.iteratorSource(prog, coll)
+}
+
+// indexedSource returns a source that reads the elements at ptr by their index
+func indexedSource(prog *Program, ptr value.Value, cond func(value.Value) (value.Value, error)) forInSource {
+	return forInSource{
+		cond: cond,
+		elem: func(index value.Value) (value.Value, error) {
+			blk := prog.Compiler.CurrentBlock()
+			addr := gep(ptr, index)
+			blk.Insts = append(blk.Insts, addr)
+			return blk.NewLoad(addr), nil
+		},
+	}
+}
+
+// iteratorSource returns a source that calls the `has_next` and `next` methods of a value
+func (n ForInNode) iteratorSource(prog *Program, coll value.Value) (forInSource, error) {
+	typ := coll.Type()
+	base := typ
+	for types.IsPointer(base) {
+		base = base.(*types.PointerType).ElemType
+	}
+	if _, isStruct := base.(*gtypes.StructType); !isStruct {
+		return forInSource{}, n.Collection.Errorf("unable to loop over a value of type %s", typ)
+	}
+
+	// The methods are called on a copy of the value, through a name that can't
+	// be used in the body of the loop
+	name := fmt.Sprintf("for.%d.iter", n.Index)
+	blk := prog.Compiler.CurrentBlock()
+	alloca := createBlockAlloca(blk.Parent, typ, name)
+	blk.NewStore(coll, alloca)
+	prog.Scope.Add(NewVariableScopeItem(name, alloca, PrivateVisibility))
+
+	method := func(field string) (value.Value, error) {
+		call := FunctionCallNode{}
+		call.NodeType = nodeFunctionCall
+		call.Token = n.Token
+		call.Name = DotReference{Base: NewIdentNode(name), Field: NewIdentNode(field)}
+		val, err := call.Codegen(prog)
+		if err != nil {
+			typeName, _ := prog.Scope.FindTypeName(base)
+			return nil, n.Collection.Errorf("unable to loop over a value of type %s, it needs has_next and next methods: %s", typeName, err)
+		}
+		return val, nil
+	}
+
+	return forInSource{
+		cond: func(value.Value) (value.Value, error) { return method("has_next") },
+		elem: func(value.Value) (value.Value, error) { return method("next") },
+	}, nil
+}
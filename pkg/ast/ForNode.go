@@ -20,6 +20,7 @@ type ForNode struct {
 	Cond  Node
 	Step  Node
 	Body  Node
+	Label string
 }
 
 func (n ForNode) String() string {
@@ -42,12 +43,13 @@ func (n ForNode) Codegen(prog *Program) (value.Value, error) {
 	var err error
 	var predicate value.Value
 	var condBlk *ir.Block
+	var condEndBlk *ir.Block
 	var bodyBlk *ir.Block
 	var bodyGenBlk *ir.Block
 	var endBlk *ir.Block
 	parentFunc := parentBlock.Parent
 
-	condBlk = parentFunc.NewBlock(namePrefix + "cond")
+	condBlk = parentFunc.NewBlock(mangleName(namePrefix + "cond"))
 
 	n.Init.Codegen(prog)
 
@@ -61,6 +63,8 @@ func (n ForNode) Codegen(prog *Program) (value.Value, error) {
 			return err
 		}
 		predicate = c
+		// The condition might have made blocks of it's own, like with `&&`
+		condEndBlk = prog.Compiler.CurrentBlock()
 		return nil
 	})
 
@@ -68,10 +72,14 @@ func (n ForNode) Codegen(prog *Program) (value.Value, error) {
 		return nil, err
 	}
 
-	bodyBlk = parentFunc.NewBlock(namePrefix + "body")
+	bodyBlk = parentFunc.NewBlock(mangleName(namePrefix + "body"))
 
-	stepBlk := parentFunc.NewBlock(namePrefix + "step")
+	stepBlk := parentFunc.NewBlock(mangleName(namePrefix + "step"))
 
+	// The end block is made before the body so break statements can jump to it
+	endBlk = parentFunc.NewBlock(mangleName(namePrefix + "end"))
+
+	prog.Compiler.PushLoop(n.Label, stepBlk, endBlk)
 	err = prog.Compiler.genInBlock(bodyBlk, func() error {
 		scp := prog.Scope
 		gen, err := n.Body.Codegen(prog)
@@ -90,6 +98,7 @@ func (n ForNode) Codegen(prog *Program) (value.Value, error) {
 	if err != nil {
 		return nil, err
 	}
+	prog.Compiler.PopLoop()
 
 	err = prog.Compiler.genInBlock(stepBlk, func() error {
 		scp := prog.Scope
@@ -103,9 +112,8 @@ func (n ForNode) Codegen(prog *Program) (value.Value, error) {
 	}
 
 	BranchIfNoTerminator(stepBlk, condBlk)
-	endBlk = parentFunc.NewBlock(namePrefix + "end")
 	prog.Compiler.PushBlock(endBlk)
-	condBlk.NewCondBr(predicate, bodyBlk, endBlk)
+	condEndBlk.NewCondBr(predicate, bodyBlk, endBlk)
 
 	if err := prog.ScopeUp(); err != nil {
 		return nil, err
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/geode-lang/geode/pkg/gtypes"
 	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
 )
@@ -59,7 +61,7 @@ func (n FunctionCallNode) Codegen(prog *Program) (value.Value, error) {
 		if ac, isAccessable := arg.(Accessable); isAccessable {
 			val, err := ac.GenAccess(prog)
 			if err != nil {
-				return nil, err
+				return nil, arg.Locate(err)
 			}
 
 			args = append(args, val)
@@ -68,14 +70,33 @@ func (n FunctionCallNode) Codegen(prog *Program) (value.Value, error) {
 				return nil, fmt.Errorf("argument to function %q failed to generate code", n.Name)
 			}
 		} else {
-			arg.SyntaxError()
-			return nil, fmt.Errorf("argument to function call to '%s' is not accessable (has no readable value). Node type %s", n.Name, arg.Kind())
+			return nil, arg.Errorf("argument to function call to '%s' is not accessable (has no readable value). Node type %s", n.Name, arg.Kind())
+		}
+	}
+
+	// The length of a fixed size array is part of it's type
+	if name, isIdent := n.Name.(IdentNode); isIdent && name.Value == "len" && len(args) == 1 {
+		if array, isArray := argTypes[0].(*types.ArrayType); isArray {
+			return constant.NewInt(types.I64, int64(array.Len)), nil
+		}
+	}
+
+	// len and append are built in for slices
+	if name, isIdent := n.Name.(IdentNode); isIdent && len(args) > 0 && gtypes.IsSlice(argTypes[0]) {
+		switch {
+		case name.Value == "len" && len(args) == 1:
+			return genSliceLen(prog, args[0]), nil
+		case name.Value == "append":
+			return genSliceAppend(prog, args[0], args[1:])
 		}
 	}
 
 	callee, prependingArgs, err := n.Name.GetFunc(prog, argTypes)
 	if err != nil {
-		return nil, err
+		if name, isNode := n.Name.(Node); isNode {
+			return nil, name.Locate(err)
+		}
+		return nil, n.Locate(err)
 	}
 	if prependingArgs != nil {
 		args = append(prependingArgs, args...)
@@ -88,12 +109,43 @@ func (n FunctionCallNode) Codegen(prog *Program) (value.Value, error) {
 	}
 
 	if callee == nil {
-		return nil, fmt.Errorf("unknown function %q referenced at %s", n.Name, n.Token.FileInfo())
+		return nil, n.Errorf("unknown function %q", n.Name)
+	}
+
+	sig, err := calleeSignature(callee)
+	if err != nil {
+		return nil, err
 	}
 
 	// Attempt to typecast all the args into the correct type
-	for i, paramType := range callee.Sig.Params {
-		args[i], _ = createTypeCast(prog, args[i], paramType)
+	for i, paramType := range sig.Params {
+		args[i], err = createTypeCast(prog, args[i], paramType)
+		if err != nil {
+			if arg := i - len(prependingArgs); arg >= 0 && arg < len(n.Args) {
+				return nil, n.Args[arg].Locate(err)
+			}
+			return nil, n.Locate(err)
+		}
+	}
+
+	// Literal formats passed to printf style functions are checked against their arguments
+	if index := formatParamIndex(callee, sig); index != -1 {
+		nodeIndex := index - len(prependingArgs)
+		if nodeIndex >= 0 {
+			if format, isLiteral := n.Args[nodeIndex].(StringNode); isLiteral {
+				checked, vals, err := prog.checkFormat(format, n.Args[nodeIndex+1:], args[index+1:])
+				if err != nil {
+					return nil, err
+				}
+				if checked != format.Value {
+					format.Value = checked
+					if args[index], err = format.Codegen(prog); err != nil {
+						return nil, err
+					}
+				}
+				copy(args[index+1:], vals)
+			}
+		}
 	}
 
 	// Varargs require type conversion to a standardized type
@@ -104,7 +156,7 @@ func (n FunctionCallNode) Codegen(prog *Program) (value.Value, error) {
 
 	for i, arg := range args {
 
-		if callee.Sig.Variadic && i >= len(callee.Params) {
+		if sig.Variadic && i >= len(sig.Params) {
 			if types.IsInt(arg.Type()) {
 				if !types.Equal(arg.Type(), types.I32) {
 					c, err := createTypeCast(prog, arg, types.I32)
@@ -134,6 +186,17 @@ func (n FunctionCallNode) Codegen(prog *Program) (value.Value, error) {
 	return prog.Compiler.CurrentBlock().NewCall(callee, arguments...), nil
 }
 
+// calleeSignature returns the function type of some callee, which can be a
+// function or a pointer to one loaded from somewhere like a vtable
+func calleeSignature(callee value.Value) (*types.FuncType, error) {
+	if ptr, isPtr := callee.Type().(*types.PointerType); isPtr {
+		if sig, isFunc := ptr.ElemType.(*types.FuncType); isFunc {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to call a value of type %s", callee.Type())
+}
+
 // Alloca implements Reference.Alloca
 func (n FunctionCallNode) Alloca(prog *Program) value.Value {
 	val, err := n.Codegen(prog)
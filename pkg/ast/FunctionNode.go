@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/geode-lang/geode/pkg/arg"
+	"github.com/geode-lang/geode/pkg/diag"
+	"github.com/geode-lang/geode/pkg/lexer"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
@@ -22,8 +24,13 @@ const (
 
 // FunctionArg represents a single argument to a function
 type FunctionArg struct {
-	Type TypeNode
-	Name string
+	Type  TypeNode
+	Name  string
+	Token lexer.Token
+}
+
+func (a FunctionArg) String() string {
+	return fmt.Sprintf("%s %s", a.Type, a.Name)
 }
 
 // FunctionNode is the representation of some function. It has methods
@@ -37,7 +44,8 @@ type FunctionNode struct {
 	Name           IdentNode
 	Args           []FunctionArg
 	Body           BlockNode
-	BodyParser     *Parser // the parser that can build the body block on demand
+	BodyParser     *Parser       // the parser the body was parsed with
+	BodyTokens     []lexer.Token // the tokens of the body, a block or an implicit return
 	External       bool
 	Variadic       bool
 	Nomangle       bool
@@ -48,6 +56,11 @@ type FunctionNode struct {
 	Package        *Package
 	IsMethod       bool
 
+	// Methods of generic classes have the type parameters of the class
+	// bound to the type arguments of the instance they belong to
+	TypeParams []string
+	TypeArgs   []types.Type
+
 	// A cache so we can remember the name of the function to codegen
 	// This is because between the Program.GetFunction, where we
 	// can compile variants, and the codegen section of the function,
@@ -64,6 +77,27 @@ type FunctionNode struct {
 	column int
 }
 
+// Signature returns the declaration of the function without it's body, like
+// `func add(int a, int b) int`
+func (n FunctionNode) Signature() string {
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "func %s(", n.Name)
+	for i, arg := range n.Args {
+		fmt.Fprintf(buff, "%s", arg)
+		if i < len(n.Args)-1 || n.Variadic {
+			fmt.Fprintf(buff, ", ")
+		}
+	}
+	if n.Variadic {
+		fmt.Fprintf(buff, "...")
+	}
+	fmt.Fprintf(buff, ")")
+	if n.ReturnType.Name != "void" || len(n.ReturnType.Modifiers) > 0 {
+		fmt.Fprintf(buff, " %s", n.ReturnType)
+	}
+	return buff.String()
+}
+
 // NameString implements Node.NameString
 func (n FunctionNode) NameString() string { return "FunctionNode" }
 
@@ -72,20 +106,16 @@ func (n FunctionNode) Arguments(prog *Program) ([]*ir.Param, []types.Type, error
 	funcArgs := make([]*ir.Param, 0)
 	argTypes := make([]types.Type, 0)
 	for _, arg := range n.Args {
-		found, _ := prog.FindType(arg.Type.Name)
-		if found == nil {
+		ty, err := arg.Type.GetType(prog)
+		if err != nil {
 			if n.HasUnknownType {
 				funcArgs = append(funcArgs, nil)
 				argTypes = append(argTypes, nil)
 				continue
 			} else {
-				return nil, nil, fmt.Errorf("unable to find type with name %q for function %s (%s)", arg.Type.Name, n.Name, n.Token.FileInfo())
+				return nil, nil, fmt.Errorf("unable to find type with name %q for function %s (%s)", arg.Type, n.Name, n.Token.FileInfo())
 			}
 		}
-		ty, err := arg.Type.GetType(prog)
-		if err != nil {
-			return nil, nil, err
-		}
 		p := ir.NewParam(arg.Name, ty)
 		funcArgs = append(funcArgs, p)
 		argTypes = append(argTypes, p.Type())
@@ -143,7 +173,7 @@ func (n FunctionNode) MangledName(prog *Program, types []types.Type) string {
 
 	ret, _ := n.ReturnType.GetType(prog)
 	if n.IsMethod {
-		return MangleFunctionName(n.Name.Value, types, ret)
+		return MangleGenericFunctionName(n.Name.Value, n.TypeArgs, types, ret)
 	}
 
 	if n.Name.Value == "main" || (n.Package != nil && n.Package.Name == "runtime") {
@@ -184,8 +214,7 @@ func (n FunctionNode) Codegen(prog *Program) (value.Value, error) {
 
 	checkerr := n.Check(prog)
 	if checkerr != nil {
-		n.SyntaxError()
-		return nil, fmt.Errorf("check error: %s", checkerr.Error())
+		return nil, n.Errorf("check error: %s", checkerr.Error())
 	}
 
 	namestring := n.Name.String()
@@ -213,17 +242,23 @@ func (n FunctionNode) Codegen(prog *Program) (value.Value, error) {
 		if len(function.Params) > 0 {
 			// prog.Compiler.CurrentBlock().AppendInst(NewLLVMComment(n.Name.String() + " arguments:"))
 		}
-		for _, arg := range function.Params {
+		for i, arg := range function.Params {
 			alloc := prog.Compiler.CurrentBlock().NewAlloca(arg.Type())
 			prog.Compiler.CurrentBlock().NewStore(arg, alloc)
 			// Set the scope item
 			scItem := NewVariableScopeItem(arg.Name(), alloc, PrivateVisibility)
 			prog.Scope.Add(scItem)
+			if i < len(n.Args) {
+				prog.defineName(n.Args[i].Token, alloc)
+				prog.vetDefine(n.Args[i].Token, alloc, true)
+			}
 		}
 		// Gen the body of the function
-		if n.BodyParser != nil {
-			n.Body = n.BodyParser.parseBlockStmt()
-		}
+		// Functions compiled inside this one, like instances of generic
+		// functions, count the assignments in their own bodies
+		outerAssignments := prog.assignments
+		prog.assignments = countAssignments(n.BodyTokens)
+		defer func() { prog.assignments = outerAssignments }()
 		var block *ir.Block
 		var ok bool
 		gen, err := n.Body.Codegen(prog)
@@ -238,7 +273,7 @@ func (n FunctionNode) Codegen(prog *Program) (value.Value, error) {
 		// if the block we ended on does not return, we need to either error or return a new void
 		if block.Term == nil {
 
-			retType, err := prog.FindType(n.ReturnType.Name)
+			retType, err := n.ReturnType.GetType(prog)
 			if err != nil {
 				return nil, err
 			}
@@ -246,6 +281,16 @@ func (n FunctionNode) Codegen(prog *Program) (value.Value, error) {
 				// Automatically return void from the function
 				// new ret interpets a nil value as returning void
 				block.NewRet(nil)
+			} else if prog.vet != nil && returns(n.Body) {
+				// When vetting, bodies where every path returns, like both
+				// branches of an if, aren't reported as missing a return
+				block.NewUnreachable()
+			} else if prog.vetting(VetMissingReturn) {
+				// Vetting reports what it finds and carries on
+				d := diag.Warningf(n.Name.Span(), "function %s can reach the end without returning a value", n.Name)
+				d.Note("it is declared to return %s", n.ReturnType)
+				prog.warn(VetMissingReturn, n.Token.Path(), d)
+				block.NewUnreachable()
 			} else {
 				return nil, fmt.Errorf("Function %s does not end in a return statement", namestring)
 			}
@@ -290,17 +335,7 @@ func createInitializationPrelude(prog *Program, n FunctionNode) {
 
 func (n FunctionNode) String() string {
 	buff := &bytes.Buffer{}
-	fmt.Fprintf(buff, "func %s(", n.Name)
-	for i, arg := range n.Args {
-		fmt.Fprintf(buff, "%s", arg)
-		if i < len(n.Args)-1 || n.Variadic {
-			fmt.Fprintf(buff, ", ")
-		}
-	}
-	if n.Variadic {
-		fmt.Fprintf(buff, "...")
-	}
-	fmt.Fprintf(buff, ") %s ", n.ReturnType)
+	fmt.Fprintf(buff, "%s ", n.Signature())
 	if n.External {
 		fmt.Fprintf(buff, "...")
 	} else {
@@ -55,6 +55,7 @@ func (n GlobalVariableDeclNode) Declare(prog *Program) (value.Value, error) {
 	scopeName := fmt.Sprintf("%s:%s", prog.Package.Name, n.Name)
 	n.Name.Value = scopeName
 	prog.Scope.GetRoot().Add(NewVariableScopeItem(scopeName, decl, PublicVisibility))
+	prog.defineName(n.Name.Token, decl)
 
 	prog.RegisterGlobalVariableInitialization(&n)
 
@@ -1,12 +1,12 @@
 package ast
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/geode-lang/geode/pkg/arg"
+	"github.com/geode-lang/geode/pkg/diag"
+	"github.com/geode-lang/geode/pkg/gtypes"
 
-	"github.com/geode-lang/geode/pkg/util/color"
 	"github.com/geode-lang/geode/pkg/util/log"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/metadata"
@@ -33,6 +33,7 @@ type IdentNode struct {
 
 	Value    string
 	NameType NameType
+	Generics []TypeNode // type arguments when constructing a generic class
 }
 
 // NewIdentNode returns a new name reference with a string as it's name
@@ -47,7 +48,20 @@ func NewIdentNode(name string) IdentNode {
 func (n IdentNode) NameString() string { return "IdentNode" }
 
 // GetFunc implements Callable.GetFunc
-func (n IdentNode) GetFunc(prog *Program, argTypes []types.Type) (*ir.Func, []value.Value, error) {
+func (n IdentNode) GetFunc(prog *Program, argTypes []types.Type) (value.Value, []value.Value, error) {
+
+	// Calling an enum variant constructs a value of it
+	enum, tag, err := prog.FindEnumVariant(n.String())
+	if err != nil {
+		return nil, nil, n.Locate(err)
+	}
+	if enum != nil {
+		variant := enum.Variants[tag]
+		if len(argTypes) != len(variant.Fields) {
+			return nil, nil, n.Errorf("enum variant %s expects %d fields. given: %d", n, len(variant.Fields), len(argTypes))
+		}
+		return prog.EnumConstructor(enum, tag), nil, nil
+	}
 
 	ns, nm := ParseName(n.String())
 	if ns == "" {
@@ -60,8 +74,50 @@ func (n IdentNode) GetFunc(prog *Program, argTypes []types.Type) (*ir.Func, []va
 		fmt.Sprintf("%s:%s", prog.Package.Name, nm),
 		nm,
 	}
+
+	// Variables holding a closure are called through it
+	if ns == prog.Scope.PackageName {
+		if addr := n.Alloca(prog); addr != nil {
+			if closure, isClosure := addr.Type().(*types.PointerType).ElemType.(*gtypes.ClosureType); isClosure {
+				return prog.callClosure(addr, closure, argTypes)
+			}
+		}
+	}
+
+	// Calling a class by name constructs a new instance of it
+	for _, name := range searchNames {
+		if class, isClass := prog.Classes[name]; isClass {
+			if len(n.Generics) > 0 {
+				args := make([]types.Type, 0, len(n.Generics))
+				for _, g := range n.Generics {
+					ty, err := g.GetType(prog)
+					if err != nil {
+						return nil, nil, err
+					}
+					args = append(args, ty)
+				}
+				inst, err := class.Instantiate(prog, args)
+				if err != nil {
+					return nil, nil, err
+				}
+				class = inst
+			}
+			if len(class.TypeParams) > 0 && class.TypeArgs == nil {
+				return nil, nil, fmt.Errorf("generic class %s must be given type arguments to be constructed", class.Name)
+			}
+			f, err := class.GenerateClassConstruction(prog, argTypes)
+			if err != nil {
+				return nil, nil, err
+			}
+			return f, nil, nil
+		}
+	}
+
 	f, err := prog.FindFunction(searchNames, argTypes)
-	return f, nil, err
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, nil, nil
 }
 
 func (n IdentNode) String() string {
@@ -70,17 +126,21 @@ func (n IdentNode) String() string {
 
 // Alloca returns the nearest alloca instruction in this scope with the given name
 func (n IdentNode) Alloca(prog *Program) value.Value {
+	return n.lookup(prog, true)
+}
+
+// lookup finds the variable the name refers to. Variables are only used by
+// looking them up to read or take the address of, not to assign
+func (n IdentNode) lookup(prog *Program, use bool) value.Value {
 
 	searchPaths := make([]string, 0)
 	searchPaths = append(searchPaths, n.Value)
 	searchPaths = append(searchPaths, fmt.Sprintf("%s:%s", prog.Package.Name, n.Value))
 
 	if prog.Scope == nil {
-		n.SyntaxError()
-		fmt.Println(n)
 		return nil
 	}
-	scopeitem, found := prog.Scope.Find(searchPaths)
+	scopeitem, scope, found := prog.Scope.FindWithScope(searchPaths)
 
 	var alloc value.Value
 	success := false
@@ -93,6 +153,18 @@ func (n IdentNode) Alloca(prog *Program) value.Value {
 		return nil
 	}
 
+	// Functions share the scope with variables, but they can't be loaded
+	if _, isVariable := scopeitem.(VariableScopeItem); !isVariable {
+		return nil
+	}
+
+	if use {
+		prog.vetUse(scopeitem.Value())
+	}
+
+	// Variables from outside of a closure are accessed through it's environment
+	scopeitem = prog.capture(prog.closure, scopeitem, scope)
+
 	if alloc, success = scopeitem.(VariableScopeItem).Value().(*ir.InstAlloca); success {
 		return alloc
 	}
@@ -101,6 +173,10 @@ func (n IdentNode) Alloca(prog *Program) value.Value {
 		return alloc
 	}
 
+	if alloc, success = scopeitem.(VariableScopeItem).Value().(*ir.InstGetElementPtr); success {
+		return alloc
+	}
+
 	log.Fatal("Unknown Type in VariableScopeItem in search paths: [%s]\n", searchPaths)
 	return nil
 }
@@ -111,19 +187,30 @@ func (n IdentNode) Load(block *ir.Block, prog *Program) *ir.InstLoad {
 	if alloc == nil {
 		return nil
 	}
+	prog.useName(n.Token, alloc)
 	return block.NewLoad(alloc)
 }
 
 // GenAssign implements Assignable.GenAssign
 func (n IdentNode) GenAssign(prog *Program, assignment value.Value, options ...AssignableOption) (value.Value, error) {
-	alloca := n.Alloca(prog)
+	alloca := n.lookup(prog, false)
 
 	if alloca == nil {
+		if c, _ := prog.lookupConstant(n.Value); c != nil {
+			return nil, n.Errorf("cannot assign to constant %s", n.Value)
+		}
 		alloca = prog.Compiler.CurrentBlock().NewAlloca(assignment.Type())
 		prog.Scope.Add(NewVariableScopeItem(n.Value, alloca, PublicVisibility))
+		prog.defineName(n.Token, alloca)
+		prog.vetDefine(n.Token, alloca, false)
+	} else {
+		prog.useName(n.Token, alloca)
 	}
 	store := prog.Compiler.CurrentBlock().NewStore(assignment, alloca)
 
+	// The variable might not point to the array it was declared with anymore
+	delete(prog.arrayLengths, alloca)
+
 	if *arg.EnableDebug {
 		mdNode := n.Token.DILocation(prog.Scope.DebugInfo)
 		md := &metadata.Attachment{
@@ -145,28 +232,49 @@ func (n IdentNode) Codegen(prog *Program) (value.Value, error) {
 func (n IdentNode) GenAccess(prog *Program) (value.Value, error) {
 	load := n.Load(prog.Compiler.CurrentBlock(), prog)
 	if load == nil {
+		c, err := prog.lookupConstant(n.Value)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			val, err := prog.constantValue(c)
+			if err != nil {
+				return nil, n.Locate(err)
+			}
+			return val, nil
+		}
 
-		buff := &bytes.Buffer{}
-		fmt.Fprintf(buff, "* unable to load/access value for identifier %s\n", color.Red(n.Value))
+		// Variants that don't carry any fields are used by name
+		enum, tag, err := prog.FindEnumVariant(n.Value)
+		if err != nil {
+			return nil, n.Locate(err)
+		}
+		if enum != nil {
+			return prog.NewEnumValue(enum, tag)
+		}
 
-		meant, dist := prog.Scope.GetSimilarName(n.Value)
-		if dist >= 0.2 {
-			fmt.Fprintf(buff, "  Maybe you meant %s", color.Green(meant))
-			if typ, found := prog.Scope.Find([]string{meant}); found {
-				ptr := typ.Value().Type().(*types.PointerType)
-				fmt.Fprintf(buff, " (%s)", ptr.ElemType)
+		d := diag.Errorf(n.Span(), "unable to load/access value for identifier %s", n.Value)
+		if meant, dist := prog.Scope.GetSimilarName(n.Value); dist >= similarNameThreshold {
+			if item, found := prog.Scope.Find([]string{meant}); found {
+				if ptr, isPtr := item.Value().Type().(*types.PointerType); isPtr {
+					d.Note("%s has type %s", meant, prog.typeInfoName(ptr.ElemType))
+				}
 			}
-			buff.WriteString("?\n")
+			// Names in the current package are written without it
+			if ns, name := ParseName(meant); ns == prog.Scope.PackageName {
+				meant = name
+			}
+			d.Suggest(n.Span(), meant, "did you mean %s?", meant)
 		}
-
-		return nil, fmt.Errorf("%s", buff)
+		return nil, d
 	}
 	return load, nil
 }
 
 // Type implements Assignable.Type
 func (n IdentNode) Type(prog *Program) (types.Type, error) {
-	ref := n.Alloca(prog)
+	// Finding the type of a variable isn't a use of it, like when it's assigned
+	ref := n.lookup(prog, false)
 
 	if alloca, success := ref.(*ir.InstAlloca); success {
 		return alloca.ElemType, nil
@@ -175,5 +283,17 @@ func (n IdentNode) Type(prog *Program) (types.Type, error) {
 	if global, success := ref.(*ir.Global); success {
 		return global.Type().(*types.PointerType).ElemType, nil
 	}
+
+	if field, success := ref.(*ir.InstGetElementPtr); success {
+		return field.Type().(*types.PointerType).ElemType, nil
+	}
+
+	if c, _ := prog.lookupConstant(n.Value); c != nil {
+		val, err := prog.constantValue(c)
+		if err != nil {
+			return nil, err
+		}
+		return val.Type(), nil
+	}
 	return nil, nil
 }
@@ -31,6 +31,7 @@ type Linker struct {
 	buildDir    string
 	objectPaths []string
 	optimize    int
+	triple      string
 }
 
 // NewLinker constructs a linker with an outpu
@@ -70,6 +71,11 @@ func (l *Linker) SetBuildDir(t string) {
 	l.buildDir = t
 }
 
+// SetTriple sets the target triple clang compiles and links for
+func (l *Linker) SetTriple(triple string) {
+	l.triple = triple
+}
+
 // SetOutput -
 func (l *Linker) SetOutput(path string) {
 	l.output = path
@@ -104,6 +110,10 @@ func (l *Linker) Run() {
 		linkArgs = append(linkArgs, optString)
 	}
 
+	if l.triple != "" {
+		linkArgs = append(linkArgs, "--target="+l.triple)
+	}
+
 	filename := l.output
 
 	hadAlternateEmission := false
@@ -111,8 +121,11 @@ func (l *Linker) Run() {
 	if *arg.EmitASM {
 		hadAlternateEmission = true
 		log.Timed("Assembly Generation", func() {
+			asmArgs := append(linkArgs, "-S", "-Wno-everything")
 			// We want to only write intel syntax. AT&T Sucks
-			asmArgs := append(linkArgs, "-S", "-masm=intel", "-Wno-everything")
+			if l.triple == "" || strings.HasPrefix(l.triple, "x86_64") {
+				asmArgs = append(asmArgs, "-masm=intel")
+			}
 			// Compile each of the objects to a .s file.
 			for _, obj := range l.objectPaths {
 				// We only want to leave user generated files in the filesystem
@@ -183,7 +196,8 @@ func (l *Linker) Run() {
 				cachefile := outbase + ".cache"
 				objFile := outbase + ".o"
 
-				hash := util.HashFile(obj)
+				// The object has to be rebuilt if the target changed
+				hash := util.HashFile(obj) + l.triple
 
 				cachedat, err := ioutil.ReadFile(cachefile)
 				if err != nil || strings.Compare(string(cachedat), hash) != 0 {
@@ -192,7 +206,11 @@ func (l *Linker) Run() {
 
 					// fmt.Printf("\tCC\t%s\n", path.Base(obj))
 					// the file doesnt exist, we need to compile it
-					out, err := util.RunCommand("clang", "-O3", "--std=c99", "-c", "-o", objFile, obj)
+					ccArgs := []string{"-O3", "--std=c99", "-c", "-o", objFile, obj}
+					if l.triple != "" {
+						ccArgs = append(ccArgs, "--target="+l.triple)
+					}
+					out, err := util.RunCommand("clang", ccArgs...)
 					if err != nil {
 						log.Fatal("(%s) %s\n", err, string(out))
 					}
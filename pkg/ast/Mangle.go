@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/geode-lang/geode/pkg/gtypes"
 	"github.com/llir/llvm/ir/types"
 )
 
@@ -65,6 +66,12 @@ const separator = `:`
 
 // MangleFunctionName will mangle a function name
 func MangleFunctionName(origName string, types []types.Type, ret types.Type) string {
+	return MangleGenericFunctionName(origName, nil, types, ret)
+}
+
+// MangleGenericFunctionName will mangle a function name along with the type
+// arguments of the generic it was instantiated from
+func MangleGenericFunctionName(origName string, generics []types.Type, types []types.Type, ret types.Type) string {
 
 	buff := &bytes.Buffer{}
 
@@ -79,15 +86,47 @@ func MangleFunctionName(origName string, types []types.Type, ret types.Type) str
 		fmt.Fprintf(buff, separator+"%s%s", prefix, p)
 	}
 
+	for _, g := range generics {
+		fmt.Fprintf(buff, separator+"G%s", mangleType(g))
+	}
+
 	for _, t := range types {
-		fmt.Fprintf(buff, separator+"T%s", t)
+		fmt.Fprintf(buff, separator+"T%s", mangleType(t))
 	}
 
-	fmt.Fprintf(buff, separator+"R%s", ret)
+	fmt.Fprintf(buff, separator+"R%s", mangleType(ret))
 
 	return buff.String()
 }
 
+// mangleType returns the name of a type in a mangled name. Tuples are written
+// as their elements, like `(i64,i8*)`, and slices as their element type, like
+// `i64[]`, so they always mangle the same no matter how their struct types are
+// printed.
+func mangleType(t types.Type) string {
+	switch t := t.(type) {
+	case *gtypes.TupleType:
+		elems := make([]string, 0, len(t.Elems))
+		for _, elem := range t.Elems {
+			elems = append(elems, mangleType(elem))
+		}
+		return fmt.Sprintf("(%s)", strings.Join(elems, ","))
+	case *gtypes.SliceType:
+		return mangleType(t.ElemType) + "[]"
+	// Unsigned integers are the same llvm types as signed ones, so pointers
+	// and arrays are named by their elements to keep them apart
+	case *types.PointerType:
+		return mangleType(t.ElemType) + "*"
+	case *types.ArrayType:
+		return fmt.Sprintf("[%d x %s]", t.Len, mangleType(t.ElemType))
+	case *types.IntType:
+		if gtypes.IsUnsigned(t) {
+			return fmt.Sprintf("u%d", t.BitSize)
+		}
+	}
+	return t.String()
+}
+
 // MangleVariableName will mangle a Variable name
 func MangleVariableName(origName string) string {
 
@@ -218,11 +257,16 @@ func UnmangleFunctionName(mangled string) (string, error) {
 	return buff.String(), nil
 }
 
-// ParseName returns the namespace and the name of a string
+// ParseName returns the namespace and the name of a string. The type arguments
+// of generic class instances, like `List<a:Person>`, are part of the name
 func ParseName(combined string) (string, string) {
 	var namespace, name string
+	args := ""
+	if i := strings.Index(combined, "<"); i != -1 {
+		combined, args = combined[:i], combined[i:]
+	}
 	parts := strings.Split(combined, separator)
-	name = parts[len(parts)-1]
+	name = parts[len(parts)-1] + args
 	if len(parts) > 1 {
 		namespace = parts[0]
 	}
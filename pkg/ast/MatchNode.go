@@ -0,0 +1,369 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// MatchNode is a match statement over integers, chars, strings or enums, example:
+// `match c { 'a', 'b' -> io:print("ab\n"); _ -> io:print("other\n"); }`.
+// Integer, char and enum matches are lowered to a switch, string matches are
+// lowered to a chain of calls to str:eq. A case for an enum variant can bind
+// the fields it carries, like `Shape:Rect(w, h) -> ...`
+type MatchNode struct {
+	NodeType
+	TokenReference
+
+	Value Node
+	Cases []MatchCase
+	Index int
+}
+
+// MatchCase is a single case of a match statement. The default case is
+// written as `_` and runs if no other case matches
+type MatchCase struct {
+	TokenReference
+
+	Patterns []Node
+	Default  bool
+	Body     Node
+}
+
+func (n MatchNode) String() string {
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "match %s {", n.Value)
+	for _, c := range n.Cases {
+		fmt.Fprintf(buff, " %s;", c)
+	}
+	fmt.Fprintf(buff, " }")
+	return buff.String()
+}
+
+func (c MatchCase) String() string {
+	buff := &bytes.Buffer{}
+	for i, pattern := range c.Patterns {
+		fmt.Fprintf(buff, "%s", pattern)
+		if i < len(c.Patterns)-1 {
+			fmt.Fprintf(buff, ", ")
+		}
+	}
+	if c.Default {
+		if len(c.Patterns) > 0 {
+			fmt.Fprintf(buff, ", ")
+		}
+		fmt.Fprintf(buff, "_")
+	}
+	fmt.Fprintf(buff, " -> %s", c.Body)
+	return buff.String()
+}
+
+// NameString implements Node.NameString
+func (n MatchNode) NameString() string { return "MatchNode" }
+
+// Codegen implements Node.Codegen for MatchNode
+func (n MatchNode) Codegen(prog *Program) (value.Value, error) {
+	val, err := n.Value.Codegen(prog)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := val.Type()
+	isString := types.Equal(typ, types.NewPointer(types.I8))
+	enum, isEnum := typ.(*gtypes.EnumType)
+	if !types.IsInt(typ) && !isString && !isEnum {
+		return nil, n.Value.Errorf("unable to match on a value of type %s", typ)
+	}
+
+	if err := n.checkCases(prog, typ); err != nil {
+		return nil, err
+	}
+
+	// The name of the blocks is prefixed so we can determine which match a block is for.
+	namePrefix := fmt.Sprintf("match.%d.", n.Index)
+	parentBlock := prog.Compiler.CurrentBlock()
+	parentFunc := parentBlock.Parent
+
+	// Enums are matched on their tag, and the fields of the variants are read
+	// from a copy of the value
+	var enumPtr value.Value
+	if isEnum {
+		enumPtr = parentBlock.NewAlloca(enum)
+		parentBlock.NewStore(val, enumPtr)
+		tag := extractValue(val, 0)
+		parentBlock.Insts = append(parentBlock.Insts, tag)
+		val = tag
+	}
+
+	endBlk := parentFunc.NewBlock(mangleName(namePrefix + "end"))
+	defaultBlk := endBlk
+
+	caseBlks := make([]*ir.Block, 0, len(n.Cases))
+	for _, c := range n.Cases {
+		blk := parentFunc.NewBlock(mangleName(namePrefix + "case"))
+		err := prog.Compiler.genInBlock(blk, func() error {
+			prog.ScopeDown(c.Token)
+			if isEnum {
+				if err := c.bindFields(prog, enum, enumPtr); err != nil {
+					return err
+				}
+			}
+			gen, err := c.Body.Codegen(prog)
+			if err != nil {
+				return err
+			}
+			BranchIfNoTerminator(gen.(*ir.Block), endBlk)
+			return prog.ScopeUp()
+		})
+		if err != nil {
+			return nil, err
+		}
+		BranchIfNoTerminator(blk, endBlk)
+
+		caseBlks = append(caseBlks, blk)
+		if c.Default {
+			defaultBlk = blk
+		}
+	}
+
+	if isString {
+		err = n.genStringChain(prog, parentBlock, val, caseBlks, defaultBlk)
+	} else {
+		err = n.genSwitch(prog, parentBlock, val, enum, caseBlks, defaultBlk)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prog.Compiler.PushBlock(endBlk)
+	return endBlk, nil
+}
+
+// checkCases reports cases that can never be reached, and patterns that
+// are used more than once
+func (n MatchNode) checkCases(prog *Program, typ types.Type) error {
+	seen := make(map[string]Node)
+	hasDefault := false
+
+	for _, c := range n.Cases {
+		if hasDefault {
+			return c.Errorf("unreachable case in match, it comes after the default case")
+		}
+		hasDefault = c.Default
+
+		for _, pattern := range c.Patterns {
+			key, err := matchPatternKey(prog, pattern, typ)
+			if err != nil {
+				return c.Locate(err)
+			}
+			if earlier, found := seen[key]; found {
+				if fmt.Sprint(earlier) != fmt.Sprint(pattern) {
+					return c.Errorf("duplicate case %s in match, it is the same %s as %s", pattern, typ, earlier)
+				}
+				return c.Errorf("duplicate case %s in match", pattern)
+			}
+			seen[key] = pattern
+		}
+
+		if len(c.Patterns) > 1 {
+			for _, pattern := range c.Patterns {
+				if _, isCall := pattern.(FunctionCallNode); isCall {
+					return c.Errorf("a case that binds the fields of a variant can only have one pattern")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchPatternKey returns a key that is the same for patterns that match the same values
+func matchPatternKey(prog *Program, pattern Node, typ types.Type) (string, error) {
+	if enum, isEnum := typ.(*gtypes.EnumType); isEnum {
+		tag, _, err := matchVariant(prog, pattern, enum)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(tag), nil
+	}
+
+	if intType, isInt := typ.(*types.IntType); isInt {
+		v, ok := matchConstant(prog, pattern)
+		if !ok {
+			return "", fmt.Errorf("match cases on %s must be integer or char constants", typ)
+		}
+		if !intFitsIn(v, intType) {
+			return "", fmt.Errorf("unreachable case %s in match, it doesn't fit in %s", pattern, typ)
+		}
+		// Values like 255 and -1 are the same byte, so they are keyed by
+		// the bits they have in the type
+		return strconv.FormatInt(truncInt(v, intType), 10), nil
+	}
+
+	str, err := prog.foldConst(pattern, prog.Package.Name)
+	if err != nil || str.kind != constString {
+		return "", fmt.Errorf("match cases on strings must be string constants")
+	}
+	return strconv.Quote(str.s), nil
+}
+
+// matchConstant returns the value of an integer or char pattern, which can be
+// any constant expression
+func matchConstant(prog *Program, pattern Node) (int64, bool) {
+	switch p := pattern.(type) {
+	case IntNode:
+		return p.Value, true
+	case CharNode:
+		return int64(p.Value), true
+	}
+	c, err := prog.foldConst(pattern, prog.Package.Name)
+	if err != nil || !c.isInt() || !c.i.IsInt64() {
+		return 0, false
+	}
+	return c.i.Int64(), true
+}
+
+// matchVariant returns the tag of an enum variant pattern, and the names
+// it binds the variant's fields to
+func matchVariant(prog *Program, pattern Node, enum *gtypes.EnumType) (int, []string, error) {
+	var name string
+	var args []Node
+
+	switch p := pattern.(type) {
+	case IdentNode:
+		name = p.Value
+	case FunctionCallNode:
+		name = fmt.Sprintf("%s", p.Name)
+		args = p.Args
+	default:
+		return -1, nil, fmt.Errorf("match cases on %s must be variants of it", prog.typeInfoName(enum))
+	}
+
+	found, tag, err := prog.FindEnumVariant(name)
+	if err != nil {
+		return -1, nil, err
+	}
+	if found == nil || !found.Equal(enum) {
+		return -1, nil, fmt.Errorf("case %s is not a variant of %s", pattern, prog.typeInfoName(enum))
+	}
+
+	variant := enum.Variants[tag]
+	if args != nil && len(args) != len(variant.Fields) {
+		return -1, nil, fmt.Errorf("enum variant %s has %d fields, but the case binds %d", name, len(variant.Fields), len(args))
+	}
+
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		ident, isIdent := arg.(IdentNode)
+		if !isIdent {
+			return -1, nil, fmt.Errorf("the fields of %s can only be bound to names", name)
+		}
+		names = append(names, ident.Value)
+	}
+	return tag, names, nil
+}
+
+// bindFields adds the fields a case binds to the scope
+func (c MatchCase) bindFields(prog *Program, enum *gtypes.EnumType, ptr value.Value) error {
+	if len(c.Patterns) != 1 {
+		return nil
+	}
+
+	tag, names, err := matchVariant(prog, c.Patterns[0], enum)
+	if err != nil {
+		return err
+	}
+
+	blk := prog.Compiler.CurrentBlock()
+	payload := enumPayload(blk, ptr, enum.Variants[tag])
+	for i, name := range names {
+		// Fields can be skipped with `_`
+		if name == "_" {
+			continue
+		}
+		field := blk.NewGetElementPtr(payload, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, int64(i)))
+		prog.Scope.Add(NewVariableScopeItem(name, field, PrivateVisibility))
+	}
+	return nil
+}
+
+// intFitsIn reports whether v can be stored in t, as either a signed or an unsigned value
+func intFitsIn(v int64, t *types.IntType) bool {
+	if t.BitSize >= 64 {
+		return true
+	}
+	min := -(int64(1) << (t.BitSize - 1))
+	max := (int64(1) << t.BitSize) - 1
+	return v >= min && v <= max
+}
+
+// truncInt returns v cut down to the bits of t, as a signed value. Bools are
+// left unsigned, as they are only ever 0 or 1
+func truncInt(v int64, t *types.IntType) int64 {
+	if t.BitSize >= 64 {
+		return v
+	}
+	if t.BitSize == 1 {
+		return v & 1
+	}
+	shift := 64 - t.BitSize
+	return v << shift >> shift
+}
+
+// genSwitch branches from blk to the case that matches an integer value. When matching
+// on an enum, val is the tag of the value.
+func (n MatchNode) genSwitch(prog *Program, blk *ir.Block, val value.Value, enum *gtypes.EnumType, caseBlks []*ir.Block, defaultBlk *ir.Block) error {
+	typ := val.Type().(*types.IntType)
+	cases := make([]*ir.Case, 0)
+	for i, c := range n.Cases {
+		for _, pattern := range c.Patterns {
+			v, _ := matchConstant(prog, pattern)
+			if enum != nil {
+				tag, _, _ := matchVariant(prog, pattern, enum)
+				v = int64(tag)
+			}
+			cases = append(cases, ir.NewCase(constant.NewInt(typ, truncInt(v, typ)), caseBlks[i]))
+		}
+	}
+	blk.NewSwitch(val, defaultBlk, cases...)
+	return nil
+}
+
+// genStringChain branches from blk to the case that matches a string value by
+// comparing it to each pattern in order
+func (n MatchNode) genStringChain(prog *Program, blk *ir.Block, val value.Value, caseBlks []*ir.Block, defaultBlk *ir.Block) error {
+	eq, err := prog.FindFunction([]string{"str:eq"}, []types.Type{val.Type(), val.Type()})
+	if err != nil {
+		return n.Errorf("matching on strings requires the \"str\" package to be included")
+	}
+
+	namePrefix := fmt.Sprintf("match.%d.", n.Index)
+	for i, c := range n.Cases {
+		for _, pattern := range c.Patterns {
+			next := blk.Parent.NewBlock(mangleName(namePrefix + "next"))
+
+			var cond value.Value
+			err := prog.Compiler.genInBlock(blk, func() error {
+				str, err := pattern.Codegen(prog)
+				if err != nil {
+					return err
+				}
+				cond, err = toBool(prog, blk.NewCall(eq, val, str))
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			blk.NewCondBr(cond, caseBlks[i], next)
+			blk = next
+		}
+	}
+	blk.NewBr(defaultBlk)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/geode-lang/geode/pkg/diag"
 	"github.com/geode-lang/geode/pkg/gtypes"
 	"github.com/geode-lang/geode/pkg/lexer"
 	"github.com/llir/llvm/ir/types"
@@ -23,17 +24,28 @@ type TokenReference struct {
 	Token lexer.Token
 }
 
-// SyntaxError -
-func (t TokenReference) SyntaxError() {
+// Span returns where in the source the node is
+func (t TokenReference) Span() diag.Span {
+	return t.Token.Span()
+}
+
+// Errorf returns an error diagnostic at the node
+func (t TokenReference) Errorf(format string, args ...interface{}) error {
+	return diag.Errorf(t.Token.Span(), format, args...)
+}
 
-	t.Token.SyntaxError()
+// Locate gives an error the node's location, unless it already has one
+func (t TokenReference) Locate(err error) error {
+	return diag.At(t.Token.Span(), err)
 }
 
 // Node -
 type Node interface {
 	fmt.Stringer
 	Kind() NodeType
-	SyntaxError()
+	Span() diag.Span
+	Errorf(format string, args ...interface{}) error
+	Locate(err error) error
 	NameString() string
 	Codegen(*Program) (value.Value, error)
 }
@@ -53,7 +65,13 @@ const (
 	nodeChar                  = "nodeChar"
 	nodeIf                    = "nodeIf"
 	nodeWhile                 = "nodeWhile"
+	nodeMatch                 = "nodeMatch"
+	nodeDefer                 = "nodeDefer"
+	nodeTuple                 = "nodeTuple"
+	nodeDestructure           = "nodeDestructure"
+	nodeSlice                 = "nodeSlice"
 	nodeFor                   = "nodeFor"
+	nodeForIn                 = "nodeForIn"
 	nodeUnary                 = "nodeUnary"
 	nodeBinary                = "nodeBinary"
 	nodeFnCall                = "nodeFnCall"
@@ -63,6 +81,11 @@ const (
 	nodeFunction              = "nodeFunction"
 	nodeFunctionCall          = "nodeFunctionCall"
 	nodeClass                 = "nodeClass"
+	nodeProtocol              = "nodeProtocol"
+	nodeEnum                  = "nodeEnum"
+	nodeClosure               = "nodeClosure"
+	nodeBreak                 = "nodeBreak"
+	nodeContinue              = "nodeContinue"
 	nodeDependency            = "nodeDependency"
 	nodeNamespace             = "nodeNamespace"
 	nodeBlock                 = "nodeBlock"
@@ -73,6 +96,7 @@ const (
 	nodeCast                  = "nodeCast"
 	nodeBool                  = "nodeBool"
 	nodeGlobalDecl            = "nodeGlobalDecl"
+	nodeConst                 = "nodeConst"
 	nodeNil                   = "nodeNil"
 	nodeIdent                 = "nodeIdent"
 	nodeStringFormat          = "nodeStringFormat"
@@ -93,7 +117,7 @@ type CharNode struct {
 
 func (n CharNode) String() string {
 	buff := &bytes.Buffer{}
-	fmt.Fprintf(buff, "CharNode")
+	fmt.Fprintf(buff, "%q", n.Value)
 	return buff.String()
 }
 
@@ -227,6 +251,7 @@ type WhileNode struct {
 	If    Node
 	Body  Node
 	Index int
+	Label string
 }
 
 func (n WhileNode) String() string {
@@ -263,6 +288,7 @@ const (
 	ModifierPointer TypeModifier = iota
 	ModifierSlice
 	ModifierUnknown
+	ModifierArray
 )
 
 // TypeNode -
@@ -270,6 +296,21 @@ type TypeNode struct {
 	PointerLevel int
 	Unknown      bool
 	Name         string
+	Generics     []TypeNode
+
+	// Closure types are written as `func(int, string) int`. Return is nil
+	// when the closure doesn't return anything
+	Closure bool
+	Params  []TypeNode
+	Return  *TypeNode
+
+	// The types of the values a function returns, when it returns more
+	// than one. Written as `(int, string)`
+	Tuple []TypeNode
+
+	// The lengths of the fixed size array modifiers, like `int[SIZE]`, in the
+	// order they are written. They must be constant expressions
+	Lengths []Node
 
 	Modifiers []TypeModifier
 }
@@ -280,12 +321,52 @@ func (n TypeNode) String() string {
 
 	fmt.Fprintf(buff, "%s", n.Name)
 
+	if len(n.Tuple) > 0 {
+		fmt.Fprintf(buff, "(")
+		for i, elem := range n.Tuple {
+			fmt.Fprintf(buff, "%s", elem)
+			if i < len(n.Tuple)-1 {
+				fmt.Fprintf(buff, ", ")
+			}
+		}
+		fmt.Fprintf(buff, ")")
+	}
+
+	if n.Closure {
+		fmt.Fprintf(buff, "(")
+		for i, param := range n.Params {
+			fmt.Fprintf(buff, "%s", param)
+			if i < len(n.Params)-1 {
+				fmt.Fprintf(buff, ", ")
+			}
+		}
+		fmt.Fprintf(buff, ")")
+		if n.Return != nil {
+			fmt.Fprintf(buff, " %s", n.Return)
+		}
+	}
+
+	if len(n.Generics) > 0 {
+		fmt.Fprintf(buff, "<")
+		for i, g := range n.Generics {
+			fmt.Fprintf(buff, "%s", g)
+			if i < len(n.Generics)-1 {
+				fmt.Fprintf(buff, ", ")
+			}
+		}
+		fmt.Fprintf(buff, ">")
+	}
+
+	lengths := n.Lengths
 	for _, mod := range n.Modifiers {
 		switch mod {
 		case ModifierPointer:
 			fmt.Fprintf(buff, "*")
 		case ModifierSlice:
 			fmt.Fprintf(buff, "[]")
+		case ModifierArray:
+			fmt.Fprintf(buff, "[%s]", lengths[0])
+			lengths = lengths[1:]
 		}
 	}
 
@@ -296,10 +377,19 @@ func (n TypeNode) String() string {
 func (n TypeNode) GetType(prog *Program) (types.Type, error) {
 	var ty types.Type
 	var err error
-	ty, err = prog.FindType(n.Name)
+	if n.Closure {
+		ty, err = n.getClosureType(prog)
+	} else if len(n.Tuple) > 0 {
+		ty, err = n.getTupleType(prog)
+	} else if len(n.Generics) > 0 {
+		ty, err = n.getGenericType(prog)
+	} else {
+		ty, err = prog.FindType(n.Name)
+	}
 	if err != nil {
 		return nil, err
 	}
+	lengths := n.Lengths
 	if len(n.Modifiers) > 0 {
 		for _, mod := range n.Modifiers {
 			switch mod {
@@ -309,6 +399,13 @@ func (n TypeNode) GetType(prog *Program) (types.Type, error) {
 				ty = gtypes.NewSlice(ty)
 			case ModifierUnknown:
 				//
+			case ModifierArray:
+				length, err := prog.arrayTypeLength(lengths[0])
+				if err != nil {
+					return nil, err
+				}
+				ty = types.NewArray(length, ty)
+				lengths = lengths[1:]
 			default:
 				return nil, fmt.Errorf("unknown type modifier %d on type %q", mod, n)
 			}
@@ -317,3 +414,62 @@ func (n TypeNode) GetType(prog *Program) (types.Type, error) {
 
 	return ty, nil
 }
+
+// getGenericType returns the instance of a generic class for the type's arguments
+func (n TypeNode) getGenericType(prog *Program) (types.Type, error) {
+	class, err := prog.FindClass(n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]types.Type, 0, len(n.Generics))
+	for _, g := range n.Generics {
+		ty, err := g.GetType(prog)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, ty)
+	}
+
+	inst, err := class.Instantiate(prog, args)
+	if err != nil {
+		return nil, err
+	}
+	return inst.typ, nil
+}
+
+// getTupleType returns the tuple type for the types of the values a function returns
+func (n TypeNode) getTupleType(prog *Program) (types.Type, error) {
+	elems := make([]types.Type, 0, len(n.Tuple))
+	for _, elem := range n.Tuple {
+		ty, err := elem.GetType(prog)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, ty)
+	}
+	return gtypes.NewTuple(elems...), nil
+}
+
+// getClosureType returns the closure type for the type's signature
+func (n TypeNode) getClosureType(prog *Program) (types.Type, error) {
+	params := make([]types.Type, 0, len(n.Params))
+	for _, param := range n.Params {
+		ty, err := param.GetType(prog)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, ty)
+	}
+
+	var ret types.Type = types.Void
+	if n.Return != nil {
+		var err error
+		ret, err = n.Return.GetType(prog)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return gtypes.NewClosure(ret, params...), nil
+}
@@ -2,7 +2,9 @@ package ast
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/geode-lang/geode/pkg/diag"
 	"github.com/geode-lang/geode/pkg/info"
 
 	"github.com/geode-lang/geode/pkg/lexer"
@@ -28,6 +30,10 @@ type Parser struct {
 	isFork             bool
 	forkParent         *Parser
 	ID                 int
+
+	// The syntax errors found so far. Forks share the list of the parser
+	// they were forked from
+	errors *diag.List
 }
 
 // NewQuickParser is used to lex and build a parser from tokens quickly
@@ -46,6 +52,7 @@ func NewParser() *Parser {
 		topLevelNodes:      make([]Node, 0),
 		binaryOpPrecedence: parserOpPrec,
 		ID:                 parserid,
+		errors:             &diag.List{},
 	}
 	parserid++
 
@@ -59,6 +66,7 @@ func (p *Parser) Fork() *Parser {
 	n.forkParent = p
 	n.isFork = true
 	n.binaryOpPrecedence = p.binaryOpPrecedence
+	n.errors = p.errors
 	n.tokenIndex = p.tokenIndex
 	n.token = p.token
 	n.tokens = p.tokens
@@ -106,9 +114,9 @@ var parserOpPrec = map[string]int{
 	"%":  40,
 }
 
-// Parse creates and runs a new lexer, that returns the
-// chan that the nodes will be passed through with
-func Parse(tokens []lexer.Token) []Node {
+// Parse parses the tokens of a file into it's top level nodes. Parsing
+// continues after syntax errors, so every error in the file is returned
+func Parse(tokens []lexer.Token) ([]Node, error) {
 	p := NewParser()
 
 	// prime the next token for use by reading from the token channel (easier than handling in .next())
@@ -120,7 +128,7 @@ func Parse(tokens []lexer.Token) []Node {
 
 	p.move(0)
 	p.parse()
-	return p.topLevelNodes
+	return p.topLevelNodes, p.errors.Err()
 }
 
 // Context returns the context of a parser
@@ -136,13 +144,11 @@ func (p *Parser) Context() *ParseContext {
 
 func (p *Parser) parse() {
 	for p.token.Type > 0 {
-		topLevelNode := p.parseTopLevelStmt()
+		topLevelNode := p.parseDecl()
 		if topLevelNode != nil {
 			p.topLevelNodes = append(p.topLevelNodes, topLevelNode)
 
 			info.AddNode(topLevelNode)
-		} else {
-			break
 		}
 	}
 }
@@ -152,8 +158,150 @@ func (p *Parser) requires(t lexer.TokenType) {
 		return
 	}
 
-	p.token.SyntaxError()
-	p.Errorf("Required token '%s' is missing. Has '%s' instead.\n", t.String(), p.token.Type.String())
+	p.syntaxError(p.token, "Required token '%s' is missing. Has '%s' instead.\n", t.String(), p.token.Type.String())
+}
+
+// bailout is panicked with to stop parsing after a syntax error. It's recovered
+// where the parser can skip to the next statement or declaration and keep going
+type bailout struct{}
+
+// syntaxError records a syntax error at a token and stops parsing the current
+// statement or declaration
+func (p *Parser) syntaxError(tok lexer.Token, format string, args ...interface{}) {
+	p.report(diag.Errorf(p.span(tok), format, args...))
+}
+
+// report records a diagnostic and stops parsing the current statement or
+// declaration, for syntax errors that come with notes or fixes
+func (p *Parser) report(d *diag.Diagnostic) {
+	p.errors.Add(d)
+	panic(bailout{})
+}
+
+// span returns the span of a token. Errors at the end of the file are reported
+// at the last token in it
+func (p *Parser) span(tok lexer.Token) diag.Span {
+	if tok.Type == lexer.TokError && len(p.tokens) > 0 {
+		tok = p.tokens[len(p.tokens)-1]
+	}
+	return tok.Span()
+}
+
+// requireTypeName records a syntax error if the current token isn't a type
+// name. Names that just aren't capitalized get the capitalized name as a fix
+func (p *Parser) requireTypeName(what string) {
+	if p.token.Is(lexer.TokType) {
+		return
+	}
+	d := diag.Errorf(p.span(p.token), "%s must be capitalized", what)
+	if p.token.Is(lexer.TokIdent) {
+		name := strings.Title(p.token.Value)
+		d.Suggest(p.token.Span(), name, "use %q instead", name)
+	}
+	p.report(d)
+}
+
+// recoverFrom stops a bailout, and reports if there was one
+func recoverFrom(r interface{}) bool {
+	if r == nil {
+		return false
+	}
+	if _, isBailout := r.(bailout); !isBailout {
+		panic(r)
+	}
+	return true
+}
+
+// parseDecl parses a top level declaration. After a syntax error the parser
+// skips to the start of the next declaration, and nil is returned
+func (p *Parser) parseDecl() (n Node) {
+	start := p.tokenIndex
+	defer func() {
+		if recoverFrom(recover()) {
+			n = nil
+			p.skipPast(start)
+			for p.token.Type > 0 && !p.atDecl() {
+				p.Next()
+			}
+		}
+	}()
+	return p.parseTopLevelStmt()
+}
+
+// parseBlockStatement parses a statement in a block. After a syntax error the
+// parser skips to the start of the next statement, and nil is returned
+func (p *Parser) parseBlockStatement() (n Node) {
+	start := p.tokenIndex
+	defer func() {
+		if recoverFrom(recover()) {
+			n = nil
+			p.skipStatement(start)
+		}
+	}()
+	n = p.parseStatement()
+	if n == nil {
+		p.syntaxError(p.token, "Unknown token in block statement\n")
+	}
+	return n
+}
+
+// skipPast moves the parser past the token at start if it hasn't moved since, so
+// skipping over a statement or declaration always makes progress
+func (p *Parser) skipPast(start int) {
+	if p.tokenIndex <= start {
+		p.tokenIndex = start
+		p.Next()
+	}
+}
+
+// skipStatement moves past the rest of a statement with a syntax error, to the
+// start of the next line or the end of the block. Blocks in the statement are
+// skipped over whole. If a declaration is found first, the block was never
+// closed, so the bailout continues out to the declaration
+func (p *Parser) skipStatement(start int) {
+	p.skipPast(start)
+	depth := 0
+	for p.token.Type > 0 {
+		if p.atDecl() {
+			panic(bailout{})
+		}
+		switch {
+		case p.token.Is(lexer.TokLeftCurly):
+			depth++
+		case p.token.Is(lexer.TokRightCurly):
+			if depth == 0 {
+				return
+			}
+			depth--
+		case depth == 0 && p.token.Is(lexer.TokSemiColon):
+			p.Next()
+			return
+		case depth == 0 && p.atLineStart():
+			return
+		}
+		p.Next()
+	}
+}
+
+// atLineStart reports if the current token is the first one on it's line
+func (p *Parser) atLineStart() bool {
+	prev := p.Peek(-1)
+	return prev.Type == lexer.TokError || prev.Line < p.token.Line
+}
+
+// atDecl reports if the parser is at what looks like the start of a top level
+// declaration, a keyword or type at the start of an unindented line
+func (p *Parser) atDecl() bool {
+	if !p.token.Is(lexer.TokFuncDefn, lexer.TokClassDefn, lexer.TokProtocolDefn, lexer.TokEnumDefn,
+		lexer.TokNamespace, lexer.TokDependency, lexer.TokConst, lexer.TokType) {
+		return false
+	}
+	return p.token.Column == 1 && p.atLineStart()
+}
+
+// Errors returns the syntax errors the parser has found
+func (p *Parser) Errors() diag.List {
+	return *p.errors
 }
 
 // Back walks the parser back one token
@@ -216,14 +364,19 @@ func (p *Parser) parseTopLevelStmt() Node {
 		return p.parseDependencyStmt()
 	case lexer.TokClassDefn:
 		return p.parseClassDefn()
+	case lexer.TokProtocolDefn:
+		return p.parseProtocolDefn()
+	case lexer.TokEnumDefn:
+		return p.parseEnumDefn()
 	case lexer.TokFuncDefn:
 		return p.parseFunctionNode()
+	case lexer.TokConst:
+		return p.parseConstDecl()
 	case lexer.TokType:
 		node := p.parseGlobalVariableDecl()
 		return node
 	}
-	p.token.SyntaxError()
-	p.Errorf("Invalid syntax in root\n")
+	p.syntaxError(p.token, "Invalid syntax in root\n")
 	return nil
 }
 
@@ -234,5 +387,5 @@ func (p *Parser) getTokenPrecedence(token string) int {
 // Errorf is a helper function to make logging easier
 func (p *Parser) Errorf(format string, a ...interface{}) error {
 
-	return fmt.Errorf("%s\n%s", p.token.SyntaxErrorS(), fmt.Sprintf(format, a...))
+	return diag.Errorf(p.token.Span(), format, a...)
 }
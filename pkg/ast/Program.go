@@ -7,11 +7,14 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 
 	"path/filepath"
 
 	"github.com/geode-lang/geode/pkg/arg"
+	"github.com/geode-lang/geode/pkg/diag"
+	"github.com/geode-lang/geode/pkg/gtypes"
 	"github.com/geode-lang/geode/pkg/lexer"
 	"github.com/geode-lang/geode/pkg/util"
 	"github.com/geode-lang/geode/pkg/util/log"
@@ -38,7 +41,39 @@ type Program struct {
 	Classes         map[string]*ClassNode
 	Initializations []*GlobalVariableDeclNode
 	StringDefs      map[string]*ir.Global
-	TypeInfoDefs    map[string]*TypeInfoDeclaration
+	TypeInfoDefs    map[string]*ir.Global
+	VTables         map[string]*ir.Global
+
+	// Overlay has text to parse in place of the files at some paths, like the
+	// unsaved buffers of an editor
+	Overlay map[string]string
+
+	closure      *closureEnv // the innermost closure being compiled
+	closureCount int
+
+	enumConstructors map[string]*ir.Func
+
+	constants map[string]*constDecl
+
+	// The syntax errors found in the files that were parsed
+	errors diag.List
+
+	// The variables the names in the program refer to, when they are being
+	// recorded, and the names the variables were defined by
+	Names   []NameRef
+	varDefs map[value.Value]lexer.Token
+
+	// What the checks the program is vetted with find, when it is
+	vet *vetter
+
+	// The lengths of the array literals that variables were last assigned,
+	// keyed by the variable's alloca. Used to bounds check subscripts
+	arrayLengths map[value.Value]int64
+
+	// How many times each name is assigned in the function being compiled.
+	// Array lengths are only known for the variables assigned once, as the
+	// code is compiled in order rather than in the order it runs
+	assignments map[string]int
 }
 
 // NewProgram creates a program and returns a pointer to it
@@ -51,7 +86,10 @@ func NewProgram() *Program {
 	p.Packages = make(map[string]*Package)
 	p.Initializations = make([]*GlobalVariableDeclNode, 0)
 	p.StringDefs = make(map[string]*ir.Global, 0)
-	p.TypeInfoDefs = make(map[string]*TypeInfoDeclaration, 0)
+	p.TypeInfoDefs = make(map[string]*ir.Global, 0)
+	p.VTables = make(map[string]*ir.Global, 0)
+	p.enumConstructors = make(map[string]*ir.Func, 0)
+	p.arrayLengths = make(map[value.Value]int64, 0)
 
 	p.TypePrecidences = make(map[types.Type]int)
 	p.TypePrecidences[types.I1] = 1
@@ -59,6 +97,12 @@ func NewProgram() *Program {
 	p.TypePrecidences[types.I16] = 3
 	p.TypePrecidences[types.I32] = 4
 	p.TypePrecidences[types.I64] = 5
+	p.TypePrecidences[gtypes.U8] = 2
+	p.TypePrecidences[gtypes.U16] = 3
+	p.TypePrecidences[gtypes.U32] = 4
+	p.TypePrecidences[gtypes.U64] = 5
+	p.TypePrecidences[types.Half] = 9
+	p.TypePrecidences[types.Float] = 10
 	p.TypePrecidences[types.Double] = 11
 	p.TypePrecidences[types.NewPointer(types.I8)] = 0
 	p.TypePrecidences[types.Void] = 0
@@ -179,11 +223,16 @@ func (p *Program) ParseText(code string, path string) {
 
 	tokens := lexer.Lex(src)
 
-	nodes := Parse(tokens)
+	// Syntax errors are collected, so every file is checked before they are reported
+	nodes, err := Parse(tokens)
+	if err != nil {
+		p.errors.Add(err)
+	}
 
 	name, err := NamespaceFromNodes(nodes)
 	if err != nil {
-		log.Fatal("Unable to decide on namespace for file %q", filepath.Clean(path))
+		p.errors.Add(fmt.Errorf("Unable to decide on namespace for file %q", filepath.Clean(path)))
+		return
 	}
 
 	r, _ := regexp.Compile("[a-z_]+")
@@ -219,6 +268,10 @@ func (p *Program) ParseText(code string, path string) {
 
 // ParseFile will parse the contents of the file at some path into a Package
 func (p *Program) ParseFile(path string) {
+	if code, found := p.Overlay[path]; found {
+		p.ParseText(code, path)
+		return
+	}
 	bytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		log.Fatal("%s\n", err)
@@ -252,6 +305,11 @@ func (p *Program) RegisterFunction(name string, fn FunctionNode) {
 
 // Congeal sets the programs module to one with nodes filled out
 func (p *Program) Congeal() (*ir.Module, error) {
+	if len(p.errors) > 0 {
+		p.errors.Sort()
+		return nil, p.errors
+	}
+
 	var err error
 	p.Module = ir.NewModule()
 
@@ -259,6 +317,7 @@ func (p *Program) Congeal() (*ir.Module, error) {
 
 	p.Functions = make(map[string]*FunctionNode)
 	p.Classes = make(map[string]*ClassNode)
+	p.constants = make(map[string]*constDecl)
 	p.Compiler = NewCompiler(p)
 
 	for _, pkg := range p.Packages {
@@ -282,11 +341,38 @@ func (p *Program) Congeal() (*ir.Module, error) {
 				cls.Package = pkg
 				p.Classes[name] = &cls
 			}
+
+			// Constants are folded when they are first used, so they can be
+			// used by the types declared below
+			if c, is := node.(ConstNode); is {
+				name := fmt.Sprintf("%s:%s", pkg.Name, c.Name)
+				if pkg.Name == "runtime" {
+					name = c.Name
+				}
+				if _, exists := p.constants[name]; exists {
+					return nil, c.Errorf("constant %s is declared more than once", name)
+				}
+				p.constants[name] = &constDecl{node: c, pkg: pkg}
+			}
 			nodes = append(nodes, PackageNode(node, pkg, p))
 		}
 	}
 
-	for _, node := range FilterPackagedNodes(nodes, nodeClass) {
+	// Generic classes are only declared when they are instantiated
+	classes := FilterPackagedNodesPredicate(nodes, func(n Node) bool {
+		cls, is := n.(ClassNode)
+		return is && len(cls.TypeParams) == 0
+	})
+
+	for _, node := range FilterPackagedNodes(nodes, nodeEnum) {
+		node.SetupContext()
+		_, err = node.Node.(EnumNode).Declare(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, node := range classes {
 		node.SetupContext()
 		_, err = node.Node.(ClassNode).Declare(p)
 		if err != nil {
@@ -294,17 +380,54 @@ func (p *Program) Congeal() (*ir.Module, error) {
 		}
 	}
 
+	for _, node := range FilterPackagedNodes(nodes, nodeProtocol) {
+		node.SetupContext()
+		_, err = node.Node.(ProtocolNode).Declare(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Protocols need every type declared before their vtables can be laid out
+	for _, node := range FilterPackagedNodes(nodes, nodeProtocol) {
+		node.SetupContext()
+		_, err = node.Node.(ProtocolNode).Codegen(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Codegen the types/classes
-	for _, node := range FilterPackagedNodes(nodes, nodeClass) {
+	for _, node := range classes {
 		node.SetupContext()
 		err := node.Node.(ClassNode).VerifyCorrectness(p)
-		util.EatError(err)
+		if err != nil {
+			return nil, err
+		}
 		_, err = node.Node.(ClassNode).Codegen(p)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// Enum payloads are sized once the classes they carry are laid out
+	for _, node := range FilterPackagedNodes(nodes, nodeEnum) {
+		node.SetupContext()
+		_, err = node.Node.(EnumNode).Codegen(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Constants that nothing uses are still checked
+	for _, pnode := range FilterPackagedNodes(nodes, nodeConst) {
+		pnode.SetupContext()
+		_, err = p.lookupConstant(pnode.Node.(ConstNode).Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, pnode := range FilterPackagedNodes(nodes, nodeGlobalDecl) {
 		pnode.SetupContext()
 		_, err = pnode.Node.(GlobalVariableDeclNode).Declare(p)
@@ -359,8 +482,9 @@ func (p *Program) FindType(name string) (types.Type, error) {
 	if found != nil {
 		return found.Type, nil
 	}
-	err := fmt.Errorf("unable to find type %q in the scope. search paths: [%s]", name, strings.Join(paths, ", "))
-	return nil, err
+	meant, dist := p.Scope.GetSimilarTypeName(paths...)
+	d := diag.Errorf(diag.Span{}, "unable to find type %q in the scope. search paths: [%s]", name, strings.Join(paths, ", "))
+	return nil, p.didYouMean(d, meant, dist)
 }
 
 // GetTypeSearchPaths creates a list of type search paths based on the current program state
@@ -385,6 +509,17 @@ func (p *Program) GetTypeSearchPaths(base string) []string {
 	return names
 }
 
+// FindClass returns the class with the given name, searching the same paths as types
+func (p *Program) FindClass(name string) (*ClassNode, error) {
+	paths := p.GetTypeSearchPaths(name)
+	for _, path := range paths {
+		if class, found := p.Classes[path]; found {
+			return class, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find class %q. search paths: [%s]", name, strings.Join(paths, ", "))
+}
+
 // FindFunction searches for a function with a searchName searchpath and the types it is being called with
 func (p *Program) FindFunction(searchNames []string, argTypes []types.Type) (*ir.Func, error) {
 	// var err error
@@ -401,7 +536,27 @@ func (p *Program) FindFunction(searchNames []string, argTypes []types.Type) (*ir
 		}
 	}
 
-	return nil, fmt.Errorf("unable to find function with names %s", searchNames)
+	names := make([]string, 0, len(p.Functions))
+	for name := range p.Functions {
+		names = append(names, name)
+	}
+	meant, dist := similarName(names, searchNames...)
+	d := diag.Errorf(diag.Span{}, "unable to find function with names %s", searchNames)
+	return nil, p.didYouMean(d, meant, dist)
+}
+
+// didYouMean adds a note to a diagnostic about a name that wasn't found,
+// suggesting a similar name if there is one
+func (p *Program) didYouMean(d *diag.Diagnostic, meant string, dist float64) *diag.Diagnostic {
+	// A name that was found but couldn't be used isn't a suggestion
+	if dist < similarNameThreshold || dist == 1 {
+		return d
+	}
+	// Names in the current package are written without it
+	if ns, name := ParseName(meant); ns == p.Scope.PackageName {
+		meant = name
+	}
+	return d.Note("did you mean %s?", meant)
 }
 
 // GetFunction takes a funciton node, detects if it is already compiled or not
@@ -410,10 +565,17 @@ func (p *Program) GetFunction(name string, options FunctionCompilationOptions) (
 
 	var err error
 
-	// Save the program state
+	// Save the program state, to restore once the function is compiled
 	previousPackage := p.Package
 	previousScope := p.Scope
 	previousCompiler := p.Compiler.Copy()
+	previousClosure := p.closure
+	defer func() {
+		p.Package = previousPackage
+		p.Scope = previousScope
+		p.Compiler = previousCompiler
+		p.closure = previousClosure
+	}()
 
 	node, exists := p.Functions[name]
 	if !exists {
@@ -421,6 +583,11 @@ func (p *Program) GetFunction(name string, options FunctionCompilationOptions) (
 		return nil, nil
 	}
 
+	// Functions never capture from the closure they happen to be compiled from,
+	// or run as part of the deferred code they are called from
+	p.closure = nil
+	p.Compiler.deferring = 0
+
 	// Prime the program's new state before compiling a function
 
 	p.Scope = p.Scope.GetRoot()
@@ -433,6 +600,11 @@ func (p *Program) GetFunction(name string, options FunctionCompilationOptions) (
 	p.ScopeDown(node.Token)
 	// p.Scope = p.Scope.SpawnChild()
 
+	// Methods of generic class instances know what their type parameters are
+	for i, param := range node.TypeParams {
+		p.Scope.RegisterType(param, node.TypeArgs[i], 0)
+	}
+
 	dopt := NewFunctionDiscoveryOptions(name, p.Package)
 	dopt.AddArgs(options.ArgTypes...)
 	NewFunctionDiscoveryWorker(p).Discover(dopt)
@@ -465,6 +637,12 @@ func (p *Program) GetFunction(name string, options FunctionCompilationOptions) (
 
 	correctTypes := make([]types.Type, 0, len(rawTypes))
 
+	// Functions that aren't given the types they are called with are compiled
+	// for the ones they declare
+	if options.ArgTypes == nil {
+		correctTypes = append(correctTypes, rawTypes...)
+	}
+
 	if options.ArgTypes != nil && !node.Variadic {
 
 		for i, expected := range rawTypes {
@@ -473,7 +651,12 @@ func (p *Program) GetFunction(name string, options FunctionCompilationOptions) (
 			given := options.ArgTypes[i]
 			unknown := nodeParamType.Unknown
 
-			if (expected != nil && given != nil) && !types.Equal(expected, given) && !typesAreLooselyEqual(given, expected) && !unknown {
+			if proto, isProto := expected.(*gtypes.ProtocolType); isProto && given != nil && !types.Equal(expected, given) {
+				// Classes can be passed as a protocol they implement
+				if _, err := p.VTable(given, proto); err != nil {
+					return nil, err
+				}
+			} else if (expected != nil && given != nil) && !types.Equal(expected, given) && !typesAreLooselyEqual(given, expected) && !unknown {
 				return nil, fmt.Errorf("incorrect type passed into function %s. given: %q, expected: %q", node.Name, given, expected)
 			}
 
@@ -516,13 +699,37 @@ func (p *Program) GetFunction(name string, options FunctionCompilationOptions) (
 		compiledVal = node.Variants[node.NameCache]
 	}
 
-	p.Package = previousPackage
-	p.Scope = previousScope
-	p.Compiler = previousCompiler
-
 	return compiledVal, nil
 }
 
+// Check compiles the functions declared in a file, so the errors in them are
+// found without building a whole program from main. Functions that take values
+// of unknown types are only compiled when they are called, so they are skipped.
+// Every function is checked, and the errors in all of them are returned
+func (p *Program) Check(path string) error {
+	errs := diag.List{}
+
+	names := make([]string, 0, len(p.Functions))
+	for name, fn := range p.Functions {
+		if fn.Token.Path() == path {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fn := p.Functions[name]
+		if fn.External || fn.HasUnknownType || len(fn.TypeParams) > 0 || fn.Compiled {
+			continue
+		}
+		if _, err := p.GetFunction(name, FunctionCompilationOptions{}); err != nil {
+			errs.Add(fn.Locate(err))
+		}
+	}
+	errs.Sort()
+	return errs.Err()
+}
+
 // GetClassMethods returns the class methods for a class with the given name
 func (p *Program) GetClassMethods(name string) ([]*FunctionNode, error) {
 
@@ -565,12 +772,18 @@ func (p *Program) Emit(buildDir string) string {
 	return llvmFileName
 }
 
+// SetTarget sets the triple the program is compiled for, and lays types out for it
+func (p *Program) SetTarget(triple string) error {
+	p.TargetTripple = triple
+	return gtypes.SetTarget(triple)
+}
+
 // String will  the LLVM IR from the package's compiler
 func (p *Program) String() string {
 	ir := &bytes.Buffer{}
 	// We need to build up the IR that will be emitted
 	// so we can track this information later on.
-	fmt.Fprintf(ir, "target datalayout = %q\n", "e-m:o-i64:64-f80:128-n8:16:32:64-S128")
+	fmt.Fprintf(ir, "target datalayout = %q\n", gtypes.Target)
 	fmt.Fprintf(ir, "target triple = %q\n", p.TargetTripple)
 
 	// Append the module information
@@ -0,0 +1,205 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// ProtocolNode is a protocol declaration, example: `protocol Addable { func value int; }`.
+// Methods only have their headers filled out, they have no body.
+type ProtocolNode struct {
+	NodeType
+	TokenReference
+
+	Package *Package
+	Name    string
+	Methods []FunctionNode
+}
+
+// NameString implements Node.NameString
+func (n ProtocolNode) NameString() string { return "ProtocolNode" }
+
+func (n ProtocolNode) String() string {
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "protocol %s {", n.Name)
+	for _, m := range n.Methods {
+		fmt.Fprintf(buff, " func %s;", m.Name)
+	}
+	fmt.Fprintf(buff, " }")
+	return buff.String()
+}
+
+// Declare a protocol type. The vtable layout is filled in by Codegen, once
+// every type a method signature could reference has been declared.
+func (n ProtocolNode) Declare(prog *Program) (value.Value, error) {
+	names := make([]string, 0, len(n.Methods))
+	seen := map[string]bool{}
+	for _, m := range n.Methods {
+		name := m.Name.String()
+		if seen[name] {
+			return nil, fmt.Errorf("protocol '%s' has two methods named '%s'", n.Name, name)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	proto := gtypes.NewProtocol(names...)
+
+	prog.Module.NewTypeDef(fmt.Sprintf("%s.vtable", n.Name), proto.VTable)
+	prog.Module.NewTypeDef(n.Name, proto)
+
+	scopeName := n.Name
+	if prog.Package.Name != "runtime" {
+		scopeName = fmt.Sprintf("%s:%s", prog.Scope.PackageName, n.Name)
+	}
+	prog.Scope.GetRoot().RegisterType(scopeName, proto, -1)
+
+	return nil, nil
+}
+
+// Codegen implements Node.Codegen for ProtocolNode
+func (n ProtocolNode) Codegen(prog *Program) (value.Value, error) {
+	found, err := prog.FindType(n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	proto := found.(*gtypes.ProtocolType)
+
+	// Every slot in the vtable takes the data pointer in place of "this"
+	slots := make([]types.Type, 0, len(n.Methods))
+	for _, m := range n.Methods {
+		params := []types.Type{types.NewPointer(types.I8)}
+		for _, arg := range m.Args {
+			ty, err := arg.Type.GetType(prog)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, ty)
+		}
+		ret, err := m.ReturnType.GetType(prog)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, types.NewPointer(types.NewFunc(ret, params...)))
+	}
+	proto.VTable.Fields = slots
+
+	return nil, nil
+}
+
+// VTable returns the vtable global that implements a protocol for some class,
+// generating it if it doesn't exist yet. If the class is missing any of the
+// protocol's methods, an error naming the method is returned.
+func (p *Program) VTable(class types.Type, proto *gtypes.ProtocolType) (*ir.Global, error) {
+	if ptr, isPtr := class.(*types.PointerType); isPtr {
+		class = ptr.ElemType
+	}
+
+	protoName, err := p.Scope.FindTypeName(proto)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, isStruct := class.(*gtypes.StructType); !isStruct {
+		return nil, fmt.Errorf("type %s cannot implement protocol %s. only classes can implement protocols", class, protoName)
+	}
+
+	className, err := p.Scope.FindTypeName(class)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s:%s", className, protoName)
+	if vtable, found := p.VTables[key]; found {
+		return vtable, nil
+	}
+
+	// The global is cached before the methods are compiled so that methods
+	// which convert "this" into the protocol don't recurse forever.
+	vtable := p.Module.NewGlobal(fmt.Sprintf("vtable.%s.%s", className, protoName), proto.VTable)
+	vtable.Immutable = true
+	p.VTables[key] = vtable
+
+	fields := make([]constant.Constant, 0, len(proto.Methods))
+	for i, method := range proto.Methods {
+		searchNames := []string{
+			fmt.Sprintf("%s.%s", className, method),
+			fmt.Sprintf("runtime:%s.%s", className, method),
+		}
+
+		sig := proto.MethodType(i)
+
+		argTypes := []types.Type{types.NewPointer(class)}
+		argTypes = append(argTypes, sig.Params[1:]...)
+
+		implemented := false
+		for _, name := range searchNames {
+			if _, exists := p.Functions[name]; exists {
+				implemented = true
+			}
+		}
+		if !implemented {
+			delete(p.VTables, key)
+			return nil, fmt.Errorf("class %s does not implement protocol %s. missing method %q", className, protoName, method)
+		}
+
+		fn, err := p.FindFunction(searchNames, argTypes)
+		if err != nil {
+			delete(p.VTables, key)
+			return nil, fmt.Errorf("class %s does not implement method %q of protocol %s: %s", className, method, protoName, err)
+		}
+
+		if !types.Equal(fn.Sig.RetType, sig.RetType) {
+			delete(p.VTables, key)
+			return nil, fmt.Errorf("method %q of class %s returns %s, but protocol %s expects %s", method, className, fn.Sig.RetType, protoName, sig.RetType)
+		}
+
+		fields = append(fields, constant.NewBitCast(fn, proto.VTable.Fields[i]))
+	}
+
+	init := constant.NewStruct(fields...)
+	init.Typ = proto.VTable
+	vtable.Init = init
+
+	return vtable, nil
+}
+
+// NewProtocolInstance converts some class instance into a protocol value. Class
+// pointers are used as the data pointer directly, while class values are copied
+// onto the heap first.
+func (p *Program) NewProtocolInstance(in value.Value, proto *gtypes.ProtocolType) (value.Value, error) {
+	vtable, err := p.VTable(in.Type(), proto)
+	if err != nil {
+		return nil, err
+	}
+
+	block := p.Compiler.CurrentBlock()
+
+	data := in
+	if class, isStruct := in.Type().(*gtypes.StructType); isStruct {
+		// https://stackoverflow.com/a/30830445
+		elemptr := constant.NewGetElementPtr(constant.NewNull(types.NewPointer(class.StructType)), constant.NewInt(types.I32, 1))
+		size := block.NewPtrToInt(elemptr, types.I32)
+
+		mem, err := p.NewRuntimeFunctionCall("xmalloc", size)
+		if err != nil {
+			return nil, err
+		}
+		data = block.NewBitCast(mem, types.NewPointer(class))
+		block.NewStore(in, data)
+	}
+
+	data = block.NewBitCast(data, types.NewPointer(types.I8))
+
+	var inst value.Value
+	inst = block.NewInsertValue(constant.NewUndef(proto), data, 0)
+	inst = block.NewInsertValue(inst, vtable, 1)
+	return inst, nil
+}
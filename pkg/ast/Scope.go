@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/geode-lang/geode/pkg/gtypes"
 	"github.com/geode-lang/geode/pkg/util"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/metadata"
@@ -33,19 +34,40 @@ func (s *Scope) Add(val ScopeItem) {
 
 // Find will traverse the scope tree to find some definition of a symbol
 func (s *Scope) Find(searchPaths []string) (ScopeItem, bool) {
+	item, _, found := s.FindWithScope(searchPaths)
+	return item, found
+}
+
+// FindWithScope is like Find, but also returns the scope the symbol was found in
+func (s *Scope) FindWithScope(searchPaths []string) (ScopeItem, *Scope, bool) {
 	for _, v := range s.Vals {
 		for _, name := range searchPaths {
 			if v.Name() == name {
-				return v, true
+				return v, s, true
 			}
 		}
 	}
 	if s.Parent != nil {
-		return s.Parent.Find(searchPaths)
+		return s.Parent.FindWithScope(searchPaths)
 	}
-	return nil, false
+	return nil, nil, false
 }
 
+// Contains reports whether some scope is this scope or one of it's children
+func (s *Scope) Contains(other *Scope) bool {
+	for other != nil {
+		if other == s {
+			return true
+		}
+		other = other.Parent
+	}
+	return false
+}
+
+// similarNameThreshold is how close a name has to be to one that wasn't found
+// for it to be suggested instead
+const similarNameThreshold = 0.8
+
 // GetSimilarName returns the most similar name in the parent scopes
 func (s *Scope) GetSimilarName(name string) (string, float64) {
 	names := s.GetNames()
@@ -54,22 +76,33 @@ func (s *Scope) GetSimilarName(name string) (string, float64) {
 		names = append(names, tmp.GetNames()...)
 		tmp = tmp.Parent
 	}
-	type info struct {
-		name string
-		dist float64
-	}
-	closest := info{
-		name: names[0],
-		dist: util.Jaro(names[0], name),
+	return similarName(names, name)
+}
+
+// GetSimilarTypeName returns the most similar type name in the parent scopes
+// to any of the names a type was searched for with
+func (s *Scope) GetSimilarTypeName(searched ...string) (string, float64) {
+	names := make([]string, 0)
+	for tmp := s; tmp != nil; tmp = tmp.Parent {
+		for name := range tmp.Types {
+			names = append(names, name)
+		}
 	}
-	for _, n := range names[1:] {
-		d := util.Jaro(n, name)
-		if d > closest.dist {
-			closest.dist = d
-			closest.name = n
+	return similarName(names, searched...)
+}
+
+// similarName returns the name that is most similar to any of the searched
+// names, and how similar they are
+func similarName(names []string, searched ...string) (string, float64) {
+	closest, dist := "", 0.0
+	for _, n := range names {
+		for _, s := range searched {
+			if d := util.Jaro(n, s); d > dist {
+				closest, dist = n, d
+			}
 		}
 	}
-	return closest.name, closest.dist
+	return closest, dist
 }
 
 // AllNames returns a recursive lookup of all names in a scope tree
@@ -152,7 +185,9 @@ func (s *Scope) FindType(names ...string) *ScopeType {
 // for an llvm type representation
 func (s *Scope) FindTypeName(t types.Type) (string, error) {
 	for _, val := range s.Types {
-		if types.Equal(val.Type, t) {
+		// Signed and unsigned integers of the same size are equal types, and
+		// so are pointers to them, so they are told apart by their mangled names
+		if types.Equal(val.Type, t) && mangleType(val.Type) == mangleType(t) {
 			return val.Name, nil
 		}
 	}
@@ -181,11 +216,19 @@ func (s *Scope) InjectPrimitives() {
 	s.RegisterType("int", types.I32, 4)
 	s.RegisterType("long", types.I64, 5)
 
+	s.RegisterType("ubyte", gtypes.U8, 2)
+	s.RegisterType("ushort", gtypes.U16, 3)
+	s.RegisterType("uint", gtypes.U32, 4)
+	s.RegisterType("ulong", gtypes.U64, 5)
+
 	s.RegisterType("big", types.NewInt(128), 128)
 	s.RegisterType("large", types.NewInt(256), 256)
 	s.RegisterType("huge", types.NewInt(512), 512)
 
+	s.RegisterType("f16", types.Half, 9)
+	s.RegisterType("f32", types.Float, 10)
 	s.RegisterType("float", types.Double, 11)
+	s.RegisterTypeAlias("f64", "float")
 	s.RegisterType("string", types.NewPointer(types.I8), 0)
 	s.RegisterType("void", types.Void, 0)
 }
@@ -195,6 +238,12 @@ func (s *Scope) RegisterType(name string, t types.Type, prec int) {
 	s.Types[name] = NewScopeType(name, t, prec)
 }
 
+// RegisterTypeAlias binds another name to a type in this scope. The type
+// keeps the name it was registered with
+func (s *Scope) RegisterTypeAlias(alias string, name string) {
+	s.Types[alias] = s.Types[name]
+}
+
 // SpawnChild takes a parent scope and creates a new variable scope for scoped variable access.
 func (s *Scope) SpawnChild() *Scope {
 	child := NewScope()
@@ -0,0 +1,179 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// SliceNode is a slice expression, example: `a[1:3]`. The slice shares the
+// storage of the value it was sliced from. Either bound can be left out when
+// slicing a slice, but slicing a pointer needs an upper bound, like `buf[:n]`
+type SliceNode struct {
+	NodeType
+	TokenReference
+
+	Source Accessable
+	Low    Accessable
+	High   Accessable
+}
+
+// NameString implements Node.NameString
+func (n SliceNode) NameString() string { return "SliceNode" }
+
+func (n SliceNode) String() string {
+	low, high := "", ""
+	if n.Low != nil {
+		low = fmt.Sprintf("%s", n.Low)
+	}
+	if n.High != nil {
+		high = fmt.Sprintf("%s", n.High)
+	}
+	return fmt.Sprintf("%s[%s:%s]", n.Source, low, high)
+}
+
+// GenAccess implements Accessable.GenAccess
+func (n SliceNode) GenAccess(prog *Program) (value.Value, error) {
+	return n.Codegen(prog)
+}
+
+// Codegen implements Node.Codegen for SliceNode
+func (n SliceNode) Codegen(prog *Program) (value.Value, error) {
+	src, err := n.Source.GenAccess(prog)
+	if err != nil {
+		return nil, err
+	}
+
+	var ptr, length, capacity value.Value
+	switch src.Type().(type) {
+	case *gtypes.SliceType:
+		ptr, length, capacity = sliceFields(prog.Compiler.CurrentBlock(), src)
+	case *types.PointerType:
+		if n.High == nil {
+			return nil, n.Errorf("slicing a pointer needs an upper bound, like %s[:n]", n.Source)
+		}
+		ptr = src
+	default:
+		return nil, n.Errorf("unable to slice a value of type %s", src.Type())
+	}
+
+	low, err := sliceBound(prog, n.Low, constant.NewInt(types.I64, 0))
+	if err != nil {
+		return nil, err
+	}
+	high, err := sliceBound(prog, n.High, length)
+	if err != nil {
+		return nil, err
+	}
+	if capacity == nil {
+		capacity = high
+	}
+
+	blk := prog.Compiler.CurrentBlock()
+	start := gep(ptr, low)
+	blk.Insts = append(blk.Insts, start)
+	return newSlice(blk, start, blk.NewSub(high, low), blk.NewSub(capacity, low)), nil
+}
+
+// sliceBound returns the value of a bound of a slice expression as an i64, or def
+// if the bound was left out
+func sliceBound(prog *Program, bound Accessable, def value.Value) (value.Value, error) {
+	if bound == nil {
+		return def, nil
+	}
+	val, err := bound.GenAccess(prog)
+	if err != nil {
+		return nil, err
+	}
+	if !types.IsInt(val.Type()) {
+		return nil, fmt.Errorf("slice bounds must be integers, not %s", val.Type())
+	}
+	return createTypeCast(prog, val, types.I64)
+}
+
+// sliceFields returns the pointer to the elements, the length and the capacity of a slice
+func sliceFields(blk *ir.Block, slice value.Value) (value.Value, value.Value, value.Value) {
+	ptr := extractValue(slice, 0)
+	length := extractValue(slice, 1)
+	capacity := extractValue(slice, 2)
+	blk.Insts = append(blk.Insts, ptr, length, capacity)
+	return ptr, length, capacity
+}
+
+// newSlice returns a slice made out of a pointer to it's elements, it's length and it's capacity
+func newSlice(blk *ir.Block, ptr, length, capacity value.Value) value.Value {
+	elem := ptr.Type().(*types.PointerType).ElemType
+	var slice value.Value = constant.NewUndef(gtypes.NewSlice(elem))
+	slice = blk.NewInsertValue(slice, ptr, 0)
+	slice = blk.NewInsertValue(slice, length, 1)
+	slice = blk.NewInsertValue(slice, capacity, 2)
+	return slice
+}
+
+// genSliceLen returns the length of a slice, for the `len` builtin
+func genSliceLen(prog *Program, slice value.Value) value.Value {
+	length := extractValue(slice, 1)
+	prog.Compiler.CurrentBlock().Insts = append(prog.Compiler.CurrentBlock().Insts, length)
+	return length
+}
+
+// genSliceAppend adds values to the end of a slice, for the `append` builtin. If the
+// slice doesn't have the capacity for them, it's storage is grown to at least twice
+// it's capacity first. The slice with the values added is returned.
+func genSliceAppend(prog *Program, slice value.Value, vals []value.Value) (value.Value, error) {
+	typ := slice.Type().(*gtypes.SliceType)
+	for i, val := range vals {
+		c, err := createTypeCast(prog, val, typ.ElemType)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = c
+	}
+
+	blk := prog.Compiler.CurrentBlock()
+	fn := blk.Parent
+	ptr, length, capacity := sliceFields(blk, slice)
+	newLength := blk.NewAdd(length, constant.NewInt(types.I64, int64(len(vals))))
+
+	growBlk := fn.NewBlock(mangleName("append_grow"))
+	doneBlk := fn.NewBlock(mangleName("append_done"))
+	blk.NewCondBr(blk.NewICmp(enum.IPredSGT, newLength, capacity), growBlk, doneBlk)
+
+	var grownPtr, grownCapacity value.Value
+	err := prog.Compiler.genInBlock(growBlk, func() error {
+		doubled := growBlk.NewMul(capacity, constant.NewInt(types.I64, 2))
+		tooSmall := growBlk.NewICmp(enum.IPredSGT, newLength, doubled)
+		grownCapacity = growBlk.NewSelect(tooSmall, newLength, doubled)
+
+		size := constant.NewInt(types.I64, int64(gtypes.ByteCount(typ.ElemType)))
+		raw := growBlk.NewBitCast(ptr, types.NewPointer(types.I8))
+		used := growBlk.NewMul(length, size)
+		grown, err := prog.NewRuntimeFunctionCall("xgrow", raw, used, growBlk.NewMul(grownCapacity, size))
+		if err != nil {
+			return err
+		}
+		grownPtr = growBlk.NewBitCast(grown, ptr.Type())
+		growBlk.NewBr(doneBlk)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prog.Compiler.PushBlock(doneBlk)
+	elems := doneBlk.NewPhi(ir.NewIncoming(ptr, blk), ir.NewIncoming(grownPtr, growBlk))
+	newCapacity := doneBlk.NewPhi(ir.NewIncoming(capacity, blk), ir.NewIncoming(grownCapacity, growBlk))
+
+	for i, val := range vals {
+		index := doneBlk.NewAdd(length, constant.NewInt(types.I64, int64(i)))
+		dest := gep(elems, index)
+		doneBlk.Insts = append(doneBlk.Insts, dest)
+		doneBlk.NewStore(val, dest)
+	}
+	return newSlice(doneBlk, elems, newLength, newCapacity), nil
+}
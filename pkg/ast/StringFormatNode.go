@@ -7,7 +7,8 @@ import (
 	"github.com/llir/llvm/ir/value"
 )
 
-// StringFormatNode -
+// StringFormatNode is a format string called with the values to format, example:
+// `"%s is %d"(name, age)`. The values are checked against the verbs in the format
 type StringFormatNode struct {
 	NodeType
 	TokenReference
@@ -21,15 +22,7 @@ func (n StringFormatNode) NameString() string { return "StringFormatNode" }
 
 // Codegen implements Node.Codegen for StringFormatNode
 func (n StringFormatNode) Codegen(prog *Program) (value.Value, error) {
-	str, err := n.Format.Codegen(prog)
-	if err != nil {
-		return nil, err
-	}
-
 	vals := make([]value.Value, 0, len(n.Args))
-
-	vals = append(vals, str)
-
 	for _, arg := range n.Args {
 		val, err := arg.Codegen(prog)
 		if err != nil {
@@ -38,7 +31,20 @@ func (n StringFormatNode) Codegen(prog *Program) (value.Value, error) {
 		vals = append(vals, val)
 	}
 
-	res, err := prog.NewRuntimeFunctionCall("__runtime_str_format", vals...)
+	// The arguments are checked against the verbs in the format, which may
+	// change it if it uses %v
+	format, vals, err := prog.checkFormat(n.Format, n.Args, vals)
+	if err != nil {
+		return nil, err
+	}
+	n.Format.Value = format
+
+	str, err := n.Format.Codegen(prog)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := prog.NewRuntimeFunctionCall("__runtime_str_format", append([]value.Value{str}, vals...)...)
 	return res, err
 }
 
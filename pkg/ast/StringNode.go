@@ -23,24 +23,29 @@ func (n StringNode) NameString() string { return "StringNode" }
 
 var strIndex = 0
 
-// Codegen implements Node.Codegen for StringNode
-func (n StringNode) Codegen(prog *Program) (value.Value, error) {
-
+// StringConstant returns a pointer to the immutable global that holds some string
+func (p *Program) StringConstant(s string) constant.Constant {
 	var str *ir.Global
 
-	if found, exists := prog.StringDefs[n.Value]; exists {
+	if found, exists := p.StringDefs[s]; exists {
 		str = found
 	} else {
 		name := fmt.Sprintf(".str.%X", strIndex)
 		strIndex++
-		str = prog.Compiler.Module.NewGlobalDef(name, newCharArray(n.Value))
+		str = p.Compiler.Module.NewGlobalDef(name, newCharArray(s))
 		str.Immutable = true
-		prog.StringDefs[n.Value] = str
+		p.StringDefs[s] = str
 	}
 
-	var val value.Value
 	zero := constant.NewInt(types.I32, 0)
-	val = constant.NewGetElementPtr(str, zero, zero)
+	return constant.NewGetElementPtr(str, zero, zero)
+}
+
+// Codegen implements Node.Codegen for StringNode
+func (n StringNode) Codegen(prog *Program) (value.Value, error) {
+
+	var val value.Value
+	val = prog.StringConstant(n.Value)
 
 	if !*arg.DisableStringDataCopy {
 		length := constant.NewInt(types.I32, int64(len([]byte(n.Value))+1))
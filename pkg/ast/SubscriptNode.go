@@ -3,9 +3,12 @@ package ast
 import (
 	"fmt"
 
+	"github.com/geode-lang/geode/pkg/arg"
 	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/geode-lang/geode/pkg/lexer"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
 )
@@ -27,8 +30,18 @@ func (n SubscriptNode) String() string {
 	return fmt.Sprintf("%s[%s]", n.Source, n.Index)
 }
 
-// GenElementPtr returns a generated GetElementPtr for this subscript operation
+// GenElementPtr returns a generated GetElementPtr for this subscript operation.
+// The index is checked against the length of the source first, unless bounds
+// checks were disabled with --no-bounds-check
 func (n SubscriptNode) GenElementPtr(prog *Program) (*ir.InstGetElementPtr, error) {
+	return n.genElementPtr(prog, !*arg.DisableBoundsCheck)
+}
+
+func (n SubscriptNode) genElementPtr(prog *Program, checked bool) (*ir.InstGetElementPtr, error) {
+	if ref, array := n.fixedArray(prog); array != nil {
+		return n.genArrayElementPtr(prog, ref, array, checked)
+	}
+
 	src, err := n.Source.GenAccess(prog)
 	if err != nil {
 		return nil, err
@@ -38,13 +51,24 @@ func (n SubscriptNode) GenElementPtr(prog *Program) (*ir.InstGetElementPtr, erro
 		return nil, err
 	}
 
+	// Slices are indexed through the pointer to their elements
 	if gtypes.IsSlice(src.Type()) {
-		fmt.Println(src.Type())
-		zero := constant.NewInt(types.I64, 0)
 		curBlock := prog.Compiler.CurrentBlock()
-		inst := gep(src, zero)
+		if checked {
+			length := extractValue(src, 1)
+			curBlock.Insts = append(curBlock.Insts, length)
+			if err := n.genBoundsCheck(prog, idx, length); err != nil {
+				return nil, err
+			}
+			curBlock = prog.Compiler.CurrentBlock()
+		}
+		inst := extractValue(src, 0)
 		src = inst
 		curBlock.Insts = append(curBlock.Insts, inst)
+	} else if length, ok := n.arrayLength(prog); ok && checked {
+		if err := n.genBoundsCheck(prog, idx, constant.NewInt(types.I64, length)); err != nil {
+			return nil, err
+		}
 	}
 	curBlock := prog.Compiler.CurrentBlock()
 	inst := gep(src, idx)
@@ -52,6 +76,170 @@ func (n SubscriptNode) GenElementPtr(prog *Program) (*ir.InstGetElementPtr, erro
 	return inst, nil
 }
 
+// fixedArray returns the source and it's type if it is a fixed size array, like
+// a variable declared as `int[4] a`
+func (n SubscriptNode) fixedArray(prog *Program) (Reference, *types.ArrayType) {
+	var ref Reference
+	switch src := n.Source.(type) {
+	case IdentNode:
+		if src.Alloca(prog) == nil {
+			return nil, nil
+		}
+		ref = src
+	case DotReference:
+		ref = src
+	default:
+		return nil, nil
+	}
+	typ, err := ref.Type(prog)
+	if err != nil {
+		return nil, nil
+	}
+	array, _ := typ.(*types.ArrayType)
+	return ref, array
+}
+
+// genArrayElementPtr indexes a fixed size array where it is stored, so the
+// elements can be assigned to. The length is always known, so it is checked
+func (n SubscriptNode) genArrayElementPtr(prog *Program, ref Reference, array *types.ArrayType, checked bool) (*ir.InstGetElementPtr, error) {
+	idx, err := n.Index.GenAccess(prog)
+	if err != nil {
+		return nil, err
+	}
+	if checked {
+		if err := n.genBoundsCheck(prog, idx, constant.NewInt(types.I64, int64(array.Len))); err != nil {
+			return nil, err
+		}
+	}
+	curBlock := prog.Compiler.CurrentBlock()
+	inst := gep(ref.Alloca(prog), constant.NewInt(types.I64, 0), idx)
+	curBlock.Insts = append(curBlock.Insts, inst)
+	return inst, nil
+}
+
+// arrayLength returns the length of the array the source refers to, if it
+// is a variable that was assigned an array literal, like `a = [1, 2, 3]`
+func (n SubscriptNode) arrayLength(prog *Program) (int64, bool) {
+	ident, isIdent := n.Source.(IdentNode)
+	if !isIdent {
+		return 0, false
+	}
+	return prog.knownArrayLength(ident)
+}
+
+// knownArrayLength returns the length of the array literal a variable was
+// assigned. It is only known if the variable is never assigned anything else,
+// as an assignment in a branch or loop might be what runs last
+func (p *Program) knownArrayLength(ident IdentNode) (int64, bool) {
+	if p.assignments[ident.Value] != 1 {
+		return 0, false
+	}
+	alloca := ident.Alloca(p)
+	if alloca == nil {
+		return 0, false
+	}
+	length, ok := p.arrayLengths[alloca]
+	return length, ok
+}
+
+// countAssignments returns how many times each name is assigned in some
+// tokens, like the body of a function. Names that have their address taken
+// count as assigned more than once, as they could be assigned through it.
+// Names declared in more than one scope are counted together, which only
+// means their lengths aren't known
+func countAssignments(tokens []lexer.Token) map[string]int {
+	counts := make(map[string]int)
+	for i, tok := range tokens {
+		if !tok.Is(lexer.TokIdent) {
+			continue
+		}
+		if i > 0 && tokens[i-1].Value == "&" {
+			counts[tok.Value] += 2
+		}
+		if i+1 < len(tokens) {
+			switch tokens[i+1].Value {
+			case "=", ":=", "+=", "-=", "*=", "/=":
+				counts[tok.Value]++
+			}
+		}
+		// The names a statement like `a, b = f()` starts with are assigned
+		// too. The last name is followed by the '=', so it's counted above
+		prev := lexer.Token{}
+		if i > 0 {
+			prev = tokens[i-1]
+		}
+		if i == 0 || prev.Line < tok.Line || prev.Is(lexer.TokLeftCurly, lexer.TokRightCurly, lexer.TokSemiColon) {
+			for _, name := range destructuredNames(tokens[i:]) {
+				counts[name]++
+			}
+		}
+	}
+	return counts
+}
+
+// destructuredNames returns the names a destructuring assignment at the start
+// of some tokens assigns to, apart from the last one
+func destructuredNames(tokens []lexer.Token) []string {
+	names := make([]string, 0)
+	i := 0
+	for i+1 < len(tokens) && tokens[i].Is(lexer.TokIdent) && tokens[i+1].Is(lexer.TokComma) {
+		names = append(names, tokens[i].Value)
+		i += 2
+	}
+	if i+1 < len(tokens) && tokens[i].Is(lexer.TokIdent) && (tokens[i+1].Value == "=" || tokens[i+1].Value == ":=") {
+		return names
+	}
+	return nil
+}
+
+// genBoundsCheck branches to a call to the runtime's __bounds_panic if idx isn't
+// within [0, length). Constant indices are checked at compile time instead.
+func (n SubscriptNode) genBoundsCheck(prog *Program, idx, length value.Value) error {
+	if c, isConst := idx.(*constant.Int); isConst {
+		i := c.X.Int64()
+		if i < 0 {
+			return n.Errorf("invalid index %d into %s (index must be non-negative)", i, n.Source)
+		}
+		l, known := length.(*constant.Int)
+		if !known {
+			return n.genBoundsPanic(prog, idx, length)
+		}
+		if i >= l.X.Int64() {
+			return n.Errorf("invalid index %d into %s (out of bounds for %d-element array)", i, n.Source, l.X.Int64())
+		}
+		return nil
+	}
+	return n.genBoundsPanic(prog, idx, length)
+}
+
+// genBoundsPanic emits the runtime check of an index against a length
+func (n SubscriptNode) genBoundsPanic(prog *Program, idx, length value.Value) error {
+	index, err := createTypeCast(prog, idx, types.I64)
+	if err != nil {
+		return err
+	}
+
+	panicFn, err := prog.GetFunction("__bounds_panic", FunctionCompilationOptions{})
+	if err != nil {
+		return err
+	}
+
+	blk := prog.Compiler.CurrentBlock()
+	fn := blk.Parent
+	okBlk := fn.NewBlock(mangleName("bounds_ok"))
+	failBlk := fn.NewBlock(mangleName("bounds_fail"))
+
+	// Comparing as unsigned catches negative indices too
+	inRange := blk.NewICmp(enum.IPredULT, index, length)
+	blk.NewCondBr(inRange, okBlk, failBlk)
+
+	failBlk.NewCall(panicFn, prog.StringConstant(n.Token.FileInfo()), index, length)
+	failBlk.NewUnreachable()
+
+	prog.Compiler.PushBlock(okBlk)
+	return nil
+}
+
 // Codegen implements Node.Codegen for SubscriptNode
 func (n SubscriptNode) Codegen(prog *Program) (value.Value, error) {
 	ptr, err := n.GenElementPtr(prog)
@@ -81,7 +269,8 @@ func (n SubscriptNode) Type(prog *Program) (types.Type, error) {
 
 	tmpBlock := ir.NewBlock("")
 
-	ptr, err := n.GenElementPtr(prog)
+	// The checks aren't needed to find the type of the element
+	ptr, err := n.genElementPtr(prog, false)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,155 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// TupleNode is the list of values returned from a function that returns
+// more than one value, example: `return q, r;`
+type TupleNode struct {
+	NodeType
+	TokenReference
+
+	Values []Node
+}
+
+// NameString implements Node.NameString
+func (n TupleNode) NameString() string { return "TupleNode" }
+
+func (n TupleNode) String() string {
+	buff := &bytes.Buffer{}
+	for i, v := range n.Values {
+		fmt.Fprintf(buff, "%s", v)
+		if i < len(n.Values)-1 {
+			fmt.Fprintf(buff, ", ")
+		}
+	}
+	return buff.String()
+}
+
+// Codegen implements Node.Codegen for TupleNode
+func (n TupleNode) Codegen(prog *Program) (value.Value, error) {
+	return n.GenTuple(prog, nil)
+}
+
+// GenTuple builds a tuple out of the values, casting each of them to the
+// matching element of typ. If typ is nil, the values keep their own types.
+func (n TupleNode) GenTuple(prog *Program, typ *gtypes.TupleType) (value.Value, error) {
+	if typ != nil && len(typ.Elems) != len(n.Values) {
+		return nil, n.Errorf("expected %d values, but %d were given", len(typ.Elems), len(n.Values))
+	}
+
+	vals := make([]value.Value, 0, len(n.Values))
+	for i, v := range n.Values {
+		val, err := v.Codegen(prog)
+		if err != nil {
+			return nil, err
+		}
+		if typ != nil && !types.Equal(val.Type(), typ.Elems[i]) {
+			val, err = createTypeCast(prog, val, typ.Elems[i])
+			if err != nil {
+				return nil, v.Locate(err)
+			}
+		}
+		vals = append(vals, val)
+	}
+
+	if typ == nil {
+		elems := make([]types.Type, 0, len(vals))
+		for _, val := range vals {
+			elems = append(elems, val.Type())
+		}
+		typ = gtypes.NewTuple(elems...)
+	}
+
+	var tuple value.Value = constant.NewUndef(typ)
+	for i, val := range vals {
+		tuple = prog.Compiler.CurrentBlock().NewInsertValue(tuple, val, uint64(i))
+	}
+	return tuple, nil
+}
+
+// DestructureNode assigns the values returned from a function to a list of
+// names, example: `q, r := divmod(7, 2);`. Names written with `:=` are
+// declared in the current scope, and values can be discarded with `_`
+type DestructureNode struct {
+	NodeType
+	TokenReference
+
+	Names   []IdentNode
+	Declare bool
+	Value   Node
+}
+
+// NameString implements Node.NameString
+func (n DestructureNode) NameString() string { return "DestructureNode" }
+
+func (n DestructureNode) String() string {
+	buff := &bytes.Buffer{}
+	for i, name := range n.Names {
+		fmt.Fprintf(buff, "%s", name)
+		if i < len(n.Names)-1 {
+			fmt.Fprintf(buff, ", ")
+		}
+	}
+	op := "="
+	if n.Declare {
+		op = ":="
+	}
+	fmt.Fprintf(buff, " %s %s", op, n.Value)
+	return buff.String()
+}
+
+// Codegen implements Node.Codegen for DestructureNode
+func (n DestructureNode) Codegen(prog *Program) (value.Value, error) {
+	val, err := n.Value.Codegen(prog)
+	if err != nil {
+		return nil, err
+	}
+
+	tuple, isTuple := val.Type().(*gtypes.TupleType)
+	if !isTuple {
+		return nil, n.Errorf("assignment mismatch: %d names but %s is a single value", len(n.Names), n.Value)
+	}
+	if len(tuple.Elems) != len(n.Names) {
+		return nil, n.Errorf("assignment mismatch: %d names but %s returns %d values", len(n.Names), n.Value, len(tuple.Elems))
+	}
+
+	for i, name := range n.Names {
+		if name.Value == "_" {
+			continue
+		}
+
+		blk := prog.Compiler.CurrentBlock()
+		elem := extractValue(val, uint64(i))
+		blk.Insts = append(blk.Insts, elem)
+
+		if n.Declare {
+			alloca := blk.NewAlloca(elem.Type())
+			blk.NewStore(elem, alloca)
+			prog.Scope.Add(NewVariableScopeItem(name.Value, alloca, PrivateVisibility))
+			prog.defineName(name.Token, alloca)
+			prog.vetDefine(name.Token, alloca, false)
+			continue
+		}
+
+		var assignment value.Value = elem
+		target, _ := name.Type(prog)
+		if target != nil && !types.Equal(elem.Type(), target) {
+			assignment, err = createTypeCast(prog, elem, target)
+			if err != nil {
+				return nil, name.Locate(err)
+			}
+		}
+		if _, err := name.GenAssign(prog, assignment); err != nil {
+			return nil, err
+		}
+	}
+	return val, nil
+}
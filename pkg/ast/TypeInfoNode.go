@@ -2,6 +2,7 @@ package ast
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/geode-lang/geode/pkg/gtypes"
 	"github.com/llir/llvm/ir"
@@ -10,13 +11,7 @@ import (
 	"github.com/llir/llvm/ir/value"
 )
 
-// TypeInfoDeclaration is what will be used in the program to keep track of globals
-type TypeInfoDeclaration struct {
-	Global  *ir.Global
-	Defined bool
-}
-
-// TypeInfoNode returns the size of a type passed into it as a function
+// TypeInfoNode returns the information about a type passed into it as a function
 type TypeInfoNode struct {
 	NodeType
 	TokenReference
@@ -29,51 +24,171 @@ func (n TypeInfoNode) NameString() string { return "TypeInfoNode" }
 
 // Codegen implements Node.Codegen for TypeInfoNode
 func (n TypeInfoNode) Codegen(prog *Program) (value.Value, error) {
-	found, ok := prog.TypeInfoDefs[n.T.String()]
-	if ok && found.Defined {
-		return found.Global, nil
-	}
-
-	analyzeType, err := n.T.GetType(prog)
+	t, err := n.T.GetType(prog)
 	if err != nil {
 		return nil, err
 	}
+	if prog.Scope.FindType("TypeInfo") == nil {
+		return nil, n.Errorf("info(%s) requires the runtime", n.T)
+	}
+	return prog.TypeInfo(t), nil
+}
 
-	// allocation was not found, so we make a new global one.
-	typ, _ := n.Type(prog)
+// TypeInfo returns the global TypeInfo of a type. The globals are constant, and
+// are shared by every info(T) call of the same type. Types that refer to other
+// types, like pointers and classes, point to the TypeInfo of those types as well.
+func (p *Program) TypeInfo(t types.Type) *ir.Global {
+	key := mangleType(t)
+	if found, ok := p.TypeInfoDefs[key]; ok {
+		return found
+	}
 
-	sct := typ.(*gtypes.StructType)
-	globl := prog.Module.NewGlobal(fmt.Sprintf("type_info_%s", n.T), sct)
+	name := fmt.Sprintf("type_info_%s", strings.Replace(key, "%", "", -1))
+	sct := p.Scope.FindType("TypeInfo").Type.(*gtypes.StructType)
+	globl := p.Module.NewGlobal(name, sct)
+	globl.Immutable = true
 
-	globl.Init = constant.NewZeroInitializer(sct)
+	// The global is added before it's fields are made, as they may refer to it
+	p.TypeInfoDefs[key] = globl
 
-	prog.TypeInfoDefs[n.T.String()] = &TypeInfoDeclaration{
-		Global:  globl,
-		Defined: false,
+	fields := map[string]constant.Constant{
+		"size":  constant.NewInt(types.I32, int64(gtypes.ByteCount(t))),
+		"align": constant.NewInt(types.I32, int64(gtypes.AlignOf(t))),
+		"name":  p.StringConstant(p.typeInfoName(t)),
 	}
 
-	// https://stackoverflow.com/a/30830445
-	elemptr := constant.NewGetElementPtr(constant.NewNull(types.NewPointer(analyzeType)), constant.NewInt(types.I32, 1))
-
-	size := prog.Compiler.CurrentBlock().NewPtrToInt(elemptr, types.I64)
+	kind := p.Scope.FindType("TypeKind").Type.(*gtypes.EnumType)
+	fields["kind"] = &constant.Struct{
+		Typ:    kind.StructType,
+		Fields: []constant.Constant{constant.NewInt(types.I32, int64(kind.VariantIndex(typeKind(t)))), constant.NewZeroInitializer(kind.Fields[1])},
+	}
 
-	nameNode := StringNode{}
-	nameNode.Value = n.T.Name
-	name, _ := nameNode.Codegen(prog)
+	zero := constant.NewInt(types.I32, 0)
+
+	switch typ := t.(type) {
+	case *types.PointerType:
+		fields["elem"] = p.TypeInfo(typ.ElemType)
+	case *gtypes.SliceType:
+		fields["elem"] = p.TypeInfo(typ.ElemType)
+	case *types.ArrayType:
+		fields["elem"] = p.TypeInfo(typ.ElemType)
+
+	// Enums also list the names of their variants, indexed by tag
+	case *gtypes.EnumType:
+		names := make([]constant.Constant, 0, len(typ.Variants))
+		for _, v := range typ.Variants {
+			names = append(names, p.StringConstant(v.Name))
+		}
+		arr := constant.NewArray(names...)
+		variants := p.Module.NewGlobalDef(name+".variants", arr)
+		variants.Immutable = true
+
+		fields["variants"] = constant.NewGetElementPtr(variants, zero, zero)
+		fields["variant_count"] = constant.NewInt(types.I32, int64(len(typ.Variants)))
+
+	// Classes list their fields, with where they are laid out
+	case *gtypes.StructType:
+		if len(typ.Fields) == 0 {
+			break
+		}
+		fieldInfo := p.Scope.FindType("FieldInfo").Type.(*gtypes.StructType)
+		infos := make([]constant.Constant, 0, len(typ.Fields))
+		for i, field := range typ.Fields {
+			name := ""
+			if i < len(typ.Names) {
+				name = typ.Names[i]
+			}
+			infos = append(infos, newConstantStruct(fieldInfo, map[string]constant.Constant{
+				"name":   p.StringConstant(name),
+				"offset": constant.NewInt(types.I32, int64(gtypes.OffsetOf(typ.StructType, i))),
+				"type":   p.TypeInfo(field),
+			}))
+		}
+		arr := constant.NewArray(infos...)
+		arr.Typ = types.NewArray(uint64(len(infos)), fieldInfo)
+		fieldsGlobl := p.Module.NewGlobalDef(name+".fields", arr)
+		fieldsGlobl.Immutable = true
+
+		fields["fields"] = constant.NewGetElementPtr(fieldsGlobl, zero, zero)
+		fields["field_count"] = constant.NewInt(types.I32, int64(len(infos)))
+	}
 
-	inst := NewClassInstance(prog, sct, map[string]value.Value{
-		"size": size,
-		"name": name,
-	})
+	globl.Init = newConstantStruct(sct, fields)
+	return globl
+}
 
-	prog.Compiler.CurrentBlock().NewStore(inst, globl)
+// newConstantStruct returns a constant instance of a class with the given fields.
+// Fields that aren't given are zero
+func newConstantStruct(sct *gtypes.StructType, fields map[string]constant.Constant) *constant.Struct {
+	values := make([]constant.Constant, len(sct.Fields))
+	for i, t := range sct.Fields {
+		values[i] = constant.NewZeroInitializer(t)
+		if i < len(sct.Names) {
+			if c, ok := fields[sct.Names[i]]; ok {
+				values[i] = c
+			}
+		}
+	}
+	return &constant.Struct{Typ: sct.StructType, Fields: values}
+}
 
-	prog.TypeInfoDefs[n.T.String()] = &TypeInfoDeclaration{
-		Global:  globl,
-		Defined: true,
+// typeInfoName returns the name of a type as it would be written in a program,
+// without the package it is in
+func (p *Program) typeInfoName(t types.Type) string {
+	if name, err := p.Scope.FindTypeName(t); err == nil {
+		_, name = ParseName(name)
+		return shortTypeName(name)
 	}
+	switch typ := t.(type) {
+	case *types.PointerType:
+		return p.typeInfoName(typ.ElemType) + "*"
+	case *gtypes.SliceType:
+		return p.typeInfoName(typ.ElemType) + "[]"
+	case *types.ArrayType:
+		return fmt.Sprintf("%s[%d]", p.typeInfoName(typ.ElemType), typ.Len)
+	case *types.VoidType:
+		return "void"
+	}
+	return mangleType(t)
+}
 
-	return globl, nil
+// typeKind returns the name of the TypeKind variant of a type
+func typeKind(t types.Type) string {
+	switch typ := t.(type) {
+	case *types.IntType:
+		if typ.BitSize == 1 {
+			return "Bool"
+		}
+		if gtypes.IsUnsigned(typ) {
+			return "Uint"
+		}
+		return "Int"
+	case *types.FloatType:
+		return "Float"
+	case *types.PointerType:
+		if types.Equal(typ.ElemType, types.I8) && !gtypes.IsUnsigned(typ.ElemType) {
+			return "String"
+		}
+		if types.IsFunc(typ.ElemType) {
+			return "Func"
+		}
+		return "Pointer"
+	case *gtypes.SliceType:
+		return "Slice"
+	case *types.ArrayType:
+		return "Array"
+	case *gtypes.StructType:
+		return "Class"
+	case *gtypes.EnumType:
+		return "Enum"
+	case *gtypes.TupleType:
+		return "Tuple"
+	case *gtypes.ClosureType, *types.FuncType:
+		return "Func"
+	case *gtypes.ProtocolType:
+		return "Protocol"
+	}
+	return "Void"
 }
 
 // GenAccess implements Accessable.Access for TypeInfoNode
@@ -87,11 +202,6 @@ func (n TypeInfoNode) String() string {
 
 // Alloca implements Reference.Alloca
 func (n TypeInfoNode) Alloca(prog *Program) value.Value {
-
-	if found, ok := prog.TypeInfoDefs[n.T.String()]; ok {
-		return found.Global
-	}
-
 	v, _ := n.Codegen(prog)
 	return v
 }
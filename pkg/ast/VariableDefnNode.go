@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 
-	"github.com/geode-lang/geode/pkg/util/log"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/types"
@@ -47,14 +46,6 @@ func (n VariableDefnNode) Codegen(prog *Program) (value.Value, error) {
 	prog.Compiler.EmptyTypeStack()
 
 	if !n.NeedsInference {
-		found, err := prog.FindType(n.Typ.Name)
-		if err != nil {
-			return nil, err
-		}
-		if found == nil {
-			n.SyntaxError()
-			log.Fatal("Unable to find type named %q for variable declaration\n", n.Typ.Name)
-		}
 		valType, err = n.Typ.GetType(prog)
 		if err != nil {
 			return nil, err
@@ -87,11 +78,13 @@ func (n VariableDefnNode) Codegen(prog *Program) (value.Value, error) {
 	prog.Compiler.PushType(alloc.ElemType)
 	scItem := NewVariableScopeItem(name.String(), alloc, PrivateVisibility)
 	prog.Scope.Add(scItem)
+	prog.defineName(name.Token, alloc)
+	prog.vetDefine(name.Token, alloc, false)
 
 	if !n.NeedsInference && val != nil {
 		val, err = createTypeCast(prog, val, alloc.ElemType)
 		if err != nil {
-			return nil, err
+			return nil, n.Locate(err)
 		}
 	}
 
@@ -102,6 +95,10 @@ func (n VariableDefnNode) Codegen(prog *Program) (value.Value, error) {
 
 	block.NewStore(val, alloc)
 
+	if array, isArray := n.Body.(ArrayNode); isArray && n.HasValue {
+		prog.arrayLengths[alloc] = int64(len(array.Elements))
+	}
+
 	return alloc, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/geode-lang/geode/pkg/arg"
+	"github.com/geode-lang/geode/pkg/diag"
 	"github.com/geode-lang/geode/pkg/gtypes"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
@@ -44,6 +45,12 @@ func (n DependencyNode) Codegen(prog *Program) (value.Value, error) { return nil
 // Codegen implements Node.Codegen for IfNode
 func (n IfNode) Codegen(prog *Program) (value.Value, error) {
 
+	if assign, isAssign := n.If.(BinaryNode); isAssign && assign.OP == "=" {
+		d := diag.Warningf(assign.Span(), "assignment used as the condition of an if")
+		d.Suggest(assign.Span(), "==", "compare the values with == instead")
+		prog.warn(VetAssignInIf, n.Token.Path(), d)
+	}
+
 	predicate, err := n.If.Codegen(prog)
 	if err != nil {
 		return nil, err
@@ -64,7 +71,7 @@ func (n IfNode) Codegen(prog *Program) (value.Value, error) {
 
 	thenBlk := parentFunc.NewBlock(mangleName(namePrefix + "then"))
 
-	prog.Compiler.genInBlock(thenBlk, func() error {
+	err = prog.Compiler.genInBlock(thenBlk, func() error {
 		gen, gerr := n.Then.Codegen(prog)
 		if gerr != nil {
 			return gerr
@@ -72,11 +79,14 @@ func (n IfNode) Codegen(prog *Program) (value.Value, error) {
 		thenGenBlk = gen.(*ir.Block)
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	elseBlk := parentFunc.NewBlock(mangleName(namePrefix + "else"))
 	var elseGenBlk *ir.Block
 
-	prog.Compiler.genInBlock(elseBlk, func() error {
+	err = prog.Compiler.genInBlock(elseBlk, func() error {
 		// We only want to construct the else block if there is one.
 		if n.Else != nil {
 			gen, gerr := n.Else.Codegen(prog)
@@ -87,6 +97,9 @@ func (n IfNode) Codegen(prog *Program) (value.Value, error) {
 		}
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	endBlk = parentFunc.NewBlock(mangleName(namePrefix + "end"))
 	prog.Compiler.PushBlock(endBlk)
@@ -124,8 +137,7 @@ func (n UnaryNode) Codegen(prog *Program) (value.Value, error) {
 
 		node, ok := n.Operand.(Reference)
 		if !ok {
-			n.SyntaxError()
-			return nil, fmt.Errorf("'&' operator called on non-addressable operand")
+			return nil, n.Errorf("'&' operator called on non-addressable operand")
 		}
 
 		return node.Alloca(prog), nil
@@ -136,8 +148,7 @@ func (n UnaryNode) Codegen(prog *Program) (value.Value, error) {
 		return nil, err
 	}
 	if operandValue == nil {
-		n.Operand.SyntaxError()
-		return nil, fmt.Errorf("nil operand")
+		return nil, n.Operand.Errorf("nil operand")
 	}
 
 	if n.Operator == "-" {
@@ -176,8 +187,7 @@ func (n UnaryNode) Codegen(prog *Program) (value.Value, error) {
 		if types.IsPointer(operandValue.Type()) {
 			return prog.Compiler.CurrentBlock().NewLoad(operandValue), nil
 		}
-		n.SyntaxError()
-		return nil, fmt.Errorf("attempt to dereference a non-pointer variable")
+		return nil, n.Errorf("attempt to dereference a non-pointer variable")
 	}
 
 	return operandValue, nil
@@ -203,18 +213,24 @@ func (n WhileNode) Codegen(prog *Program) (value.Value, error) {
 		return nil, err
 	}
 	one := constant.NewInt(types.I1, 1)
-	prog.Compiler.PopBlock()
-	BranchIfNoTerminator(parentBlock, startblock)
 	c, err := createTypeCast(prog, predicate, types.I1)
 	if err != nil {
 		return nil, err
 	}
-	predicate = startblock.NewICmp(enum.IPredEQ, one, c)
-
-	var endBlk *ir.Block
+	// The condition might have made blocks of it's own, like with `&&`,
+	// so the loop branches from the block the condition ended in
+	condEndBlk := prog.Compiler.CurrentBlock()
+	prog.Compiler.PopBlock()
+	BranchIfNoTerminator(parentBlock, startblock)
+	predicate = condEndBlk.NewICmp(enum.IPredEQ, one, c)
 
 	bodyBlk := parentFunc.NewBlock(mangleName(namePrefix + "body"))
+
+	// The merge block is made before the body so break statements can jump to it
+	endBlk := parentFunc.NewBlock(mangleName(namePrefix + "merge"))
+
 	prog.Compiler.PushBlock(bodyBlk)
+	prog.Compiler.PushLoop(n.Label, startblock, endBlk)
 
 	v, err := n.Body.Codegen(prog)
 	if err != nil {
@@ -222,16 +238,17 @@ func (n WhileNode) Codegen(prog *Program) (value.Value, error) {
 	}
 	bodyGenBlk := v.(*ir.Block)
 
+	prog.Compiler.PopLoop()
+
 	// If there is no terminator for the block, IE: no return
 	// branch to the merge block
 
-	endBlk = parentFunc.NewBlock(mangleName(namePrefix + "merge"))
 	prog.Compiler.PushBlock(endBlk)
 
 	BranchIfNoTerminator(bodyBlk, startblock)
 	BranchIfNoTerminator(bodyGenBlk, startblock)
 
-	startblock.NewCondBr(predicate, bodyBlk, endBlk)
+	condEndBlk.NewCondBr(predicate, bodyBlk, endBlk)
 
 	// branchIfNoTerminator(c.CurrentBlock(), endBlk)
 
@@ -268,10 +285,33 @@ func createTypeCast(prog *Program, in value.Value, to types.Type) (value.Value,
 
 	// If the cast would not change the type, just return the in value, nil
 	if types.Equal(inType, to) {
+		// Integers that only differ in sign are given the new type, so the
+		// operations on them pick the right instructions
+		if types.IsInt(to) && gtypes.IsUnsigned(inType) != gtypes.IsUnsigned(to) {
+			if c, ok := in.(*constant.Int); ok {
+				return constant.NewInt(to.(*types.IntType), c.X.Int64()), nil
+			}
+			return prog.Compiler.CurrentBlock().NewBitCast(in, to), nil
+		}
 		return in, nil
 	}
 
+	if proto, ok := to.(*gtypes.ProtocolType); ok {
+		return prog.NewProtocolInstance(in, proto)
+	}
+
+	// The tag of an enum value is the index of it's variant
+	if _, ok := inType.(*gtypes.EnumType); ok && toInt {
+		tag := extractValue(in, 0)
+		prog.Compiler.CurrentBlock().Insts = append(prog.Compiler.CurrentBlock().Insts, tag)
+		return createTypeCast(prog, tag, to)
+	}
+
 	if c, ok := in.(*constant.Int); ok && types.IsInt(to) {
+		// Constants that don't fit in the type would silently change value
+		if !constFits(c.X, to.(*types.IntType)) {
+			return nil, fmt.Errorf("constant %s overflows %s", c.X, prog.typeInfoName(to))
+		}
 		c.Typ = to.(*types.IntType)
 		return c, nil
 	}
@@ -290,15 +330,25 @@ func createTypeCast(prog *Program, in value.Value, to types.Type) (value.Value,
 	}
 
 	if fromFloat && toInt {
+		if gtypes.IsUnsigned(to) {
+			return prog.Compiler.CurrentBlock().NewFPToUI(in, to), nil
+		}
 		return prog.Compiler.CurrentBlock().NewFPToSI(in, to), nil
 	}
 
 	if fromInt && toFloat {
+		if gtypes.IsUnsigned(inType) {
+			return prog.Compiler.CurrentBlock().NewUIToFP(in, to), nil
+		}
 		return prog.Compiler.CurrentBlock().NewSIToFP(in, to), nil
 	}
 
 	if fromInt && toInt {
 		if inSize < outSize {
+			// Unsigned integers are zero extended
+			if gtypes.IsUnsigned(inType) {
+				return prog.Compiler.CurrentBlock().NewZExt(in, to), nil
+			}
 			return prog.Compiler.CurrentBlock().NewSExt(in, to), nil
 		}
 		if inSize == outSize {
@@ -339,7 +389,25 @@ func (n ReturnNode) Codegen(prog *Program) (value.Value, error) {
 	var retVal value.Value
 	var err error
 
-	if !prog.Compiler.CurrentFunc().Sig.RetType.Equal(types.Void) {
+	if prog.Compiler.deferring > 0 {
+		return nil, n.Errorf("return statement in deferred code")
+	}
+
+	expected := prog.Compiler.CurrentFunc().Sig.RetType
+	tuple, returnsTuple := expected.(*gtypes.TupleType)
+	values, isTuple := n.Value.(TupleNode)
+
+	if isTuple && !returnsTuple {
+		fnName, _ := UnmangleFunctionName(prog.Compiler.CurrentFunc().Name())
+		return nil, n.Errorf("too many return values for function %s. expected 1, given %d", fnName, len(values.Values))
+	}
+
+	if isTuple && returnsTuple {
+		retVal, err = values.GenTuple(prog, tuple)
+		if err != nil {
+			return nil, err
+		}
+	} else if !expected.Equal(types.Void) {
 		if n.Value != nil {
 			retVal, err = n.Value.Codegen(prog)
 			if err != nil {
@@ -350,12 +418,14 @@ func (n ReturnNode) Codegen(prog *Program) (value.Value, error) {
 			expected := prog.Compiler.CurrentFunc().Sig.RetType
 			if !types.Equal(given, expected) {
 				if !(types.IsInt(given) && types.IsInt(expected)) {
-					n.SyntaxError()
 					fnName, err := UnmangleFunctionName(prog.Compiler.CurrentFunc().Name())
 					if err != nil {
 
 						return nil, err
 					}
+					if returnsTuple {
+						return nil, n.Errorf("not enough return values for function %s. expected %d, given 1", fnName, len(tuple.Elems))
+					}
 					expectedName, err := prog.Scope.FindTypeName(expected)
 					if err != nil {
 
@@ -367,7 +437,7 @@ func (n ReturnNode) Codegen(prog *Program) (value.Value, error) {
 						return nil, err
 					}
 
-					return nil, fmt.Errorf("incorrect return value for function %s. expected: %s (%s). given: %s (%s)", fnName, expectedName, expected, givenName, given)
+					return nil, n.Errorf("incorrect return value for function %s. expected: %s (%s). given: %s (%s)", fnName, expectedName, expected, givenName, given)
 				}
 				retVal, err = createTypeCast(prog, retVal, prog.Compiler.CurrentFunc().Sig.RetType)
 				if err != nil {
@@ -381,6 +451,10 @@ func (n ReturnNode) Codegen(prog *Program) (value.Value, error) {
 		}
 	}
 
+	if err := prog.runDefers(0); err != nil {
+		return nil, err
+	}
+
 	ret := prog.Compiler.CurrentBlock().NewRet(retVal)
 
 	if *arg.EnableDebug {
@@ -466,5 +540,30 @@ func gep(src value.Value, indices ...value.Value) *ir.InstGetElementPtr {
 	if elemPtr != nil {
 		*elemPtr = elem
 	}
+	// Only stepping over the pointer keeps the element's Geode type
+	if _, isPtr := src.Type().(*types.PointerType); isPtr && elemPtr != nil && len(indices) == 1 {
+		inst.ElemType = elem
+		inst.Typ = types.NewPointer(elem)
+	}
+	return inst
+}
+
+// extractValue returns a new extractvalue instruction. The type of the element
+// is resolved up front, as ir.NewExtractValue is not aware of Geode types.
+func extractValue(x value.Value, indices ...uint64) *ir.InstExtractValue {
+	inst := ir.NewExtractValue(x, indices...)
+	elem := x.Type()
+	for _, index := range indices {
+		if t, ok := elem.(gtypes.Type); ok {
+			elem = t.Underlying()
+		}
+		switch t := elem.(type) {
+		case *types.StructType:
+			elem = t.Fields[index]
+		case *types.ArrayType:
+			elem = t.ElemType
+		}
+	}
+	inst.Typ = elem
 	return inst
 }
@@ -0,0 +1,192 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// formatVerb is a single verb in a format string, like `%-8.2f`
+type formatVerb struct {
+	Verb byte
+	// The index just past the verb in the format string
+	End int
+	// The number of `*` widths and precisions, which each take an int argument
+	Stars int
+}
+
+// parseFormat returns the verbs in a printf style format string
+func parseFormat(format string) ([]formatVerb, error) {
+	verbs := make([]formatVerb, 0)
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '%' {
+			continue
+		}
+
+		v := formatVerb{}
+
+		// Flags, width, precision and length modifiers
+		for i < len(format) && strings.IndexByte("-+ #0123456789.*hlLqjzt", format[i]) != -1 {
+			if format[i] == '*' {
+				v.Stars++
+			}
+			i++
+		}
+		if i >= len(format) {
+			return nil, fmt.Errorf("format %q ends with an unfinished verb", format)
+		}
+		if strings.IndexByte("diuxXocfFeEgGaAspv", format[i]) == -1 {
+			return nil, fmt.Errorf("unknown verb %%%c in format %q", format[i], format)
+		}
+		v.Verb = format[i]
+		v.End = i + 1
+		verbs = append(verbs, v)
+	}
+	return verbs, nil
+}
+
+// formatParamIndex returns the index of the format parameter of a function that
+// formats it's variadic arguments like printf, or -1 if it doesn't. These are
+// variadic functions whose last parameter is a string named format or fmt
+func formatParamIndex(callee value.Value, sig *types.FuncType) int {
+	fn, isFunc := callee.(*ir.Func)
+	if !isFunc || !sig.Variadic || len(fn.Params) == 0 {
+		return -1
+	}
+	last := fn.Params[len(fn.Params)-1]
+	if !types.Equal(last.Typ, types.NewPointer(types.I8)) {
+		return -1
+	}
+	if name := last.Name(); name != "format" && name != "fmt" {
+		return -1
+	}
+	return len(fn.Params) - 1
+}
+
+// checkFormat checks the arguments given to a format string against it's verbs,
+// and reports mismatches at the argument. The %v verb is replaced with the verb
+// the argument's type is formatted with, so the format and arguments to use
+// are returned.
+func (p *Program) checkFormat(format StringNode, args []Node, vals []value.Value) (string, []value.Value, error) {
+	verbs, err := parseFormat(format.Value)
+	if err != nil {
+		return "", nil, format.Locate(err)
+	}
+
+	needed := 0
+	for _, v := range verbs {
+		needed += v.Stars + 1
+	}
+	if needed != len(args) {
+		var at Node = format
+		if len(args) > needed {
+			at = args[needed]
+		}
+		return "", nil, at.Errorf("format %q needs %d arguments, but was given %d", format.Value, needed, len(args))
+	}
+
+	out := &strings.Builder{}
+	last := 0
+	arg := 0
+	for _, v := range verbs {
+		for i := 0; i < v.Stars; i++ {
+			if !types.IsInt(vals[arg].Type()) {
+				return "", nil, args[arg].Errorf("the * in format %q takes an int, but %s is %s", format.Value, args[arg], p.typeInfoName(vals[arg].Type()))
+			}
+			arg++
+		}
+
+		verb := v.Verb
+		if verb == 'v' {
+			verb, vals[arg], err = p.formatValue(vals[arg])
+			if err != nil {
+				return "", nil, args[arg].Errorf("%%v can't format %s, it is %s", args[arg], p.typeInfoName(vals[arg].Type()))
+			}
+		}
+
+		if want, ok := formatVerbAccepts(verb, vals[arg].Type()); !ok {
+			return "", nil, args[arg].Errorf("%%%c in format %q expects %s, but %s is %s", v.Verb, format.Value, want, args[arg], p.typeInfoName(vals[arg].Type()))
+		}
+
+		out.WriteString(format.Value[last : v.End-1])
+		out.WriteByte(verb)
+		last = v.End
+		arg++
+	}
+	out.WriteString(format.Value[last:])
+
+	return out.String(), vals, nil
+}
+
+// formatVerbAccepts reports if a value of some type can be formatted with a verb.
+// If it can't, the kind of value the verb expects is returned as well
+func formatVerbAccepts(verb byte, t types.Type) (string, bool) {
+	switch verb {
+	case 'd', 'i', 'u', 'x', 'X', 'o', 'c':
+		return "an integer", types.IsInt(t)
+	case 'f', 'F', 'e', 'E', 'g', 'G', 'a', 'A':
+		return "a float", types.IsFloat(t)
+	case 's':
+		return "a string", types.Equal(t, types.NewPointer(types.I8))
+	case 'p':
+		return "a pointer", types.IsPointer(t)
+	}
+	return "nothing", false
+}
+
+// formatValue returns the verb %v formats a value with. Bools and enums are
+// formatted by their names, so the value to format is returned as well
+func (p *Program) formatValue(val value.Value) (byte, value.Value, error) {
+	blk := p.Compiler.CurrentBlock()
+
+	switch t := val.Type().(type) {
+	case *types.IntType:
+		if t.BitSize == 1 {
+			return 's', blk.NewSelect(val, p.StringConstant("true"), p.StringConstant("false")), nil
+		}
+		if gtypes.IsUnsigned(t) {
+			return 'u', val, nil
+		}
+		return 'd', val, nil
+
+	case *types.FloatType:
+		return 'g', val, nil
+
+	case *types.PointerType:
+		if types.Equal(t, types.NewPointer(types.I8)) {
+			return 's', val, nil
+		}
+		return 'p', val, nil
+
+	// Enums are formatted by the name of their variant, which is read from
+	// the names in their TypeInfo
+	case *gtypes.EnumType:
+		if p.Scope.FindType("TypeInfo") == nil {
+			break
+		}
+		info := p.TypeInfo(t)
+		infoType := info.ContentType.(*gtypes.StructType)
+		zero := constant.NewInt(types.I32, 0)
+
+		namesPtr := gep(info, zero, constant.NewInt(types.I32, int64(infoType.FieldIndex("variants"))))
+		blk.Insts = append(blk.Insts, namesPtr)
+		names := blk.NewLoad(namesPtr)
+		tag := extractValue(val, 0)
+		blk.Insts = append(blk.Insts, tag)
+		name := gep(names, tag)
+		blk.Insts = append(blk.Insts, name)
+		return 's', blk.NewLoad(name), nil
+	}
+
+	return 0, val, fmt.Errorf("unable to format a value of type %s", val.Type())
+}
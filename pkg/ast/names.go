@@ -0,0 +1,43 @@
+package ast
+
+import (
+	"github.com/geode-lang/geode/pkg/lexer"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// NameRef is a name in the source that refers to a variable, along with the
+// type of the variable and the name it was defined by. Programs record them as
+// they are compiled when asked to, for tools like the language server
+type NameRef struct {
+	Token lexer.Token
+	Def   lexer.Token
+	Type  string
+}
+
+// RecordNames makes the program record the variables the names in it refer to
+// as it is compiled, in Names
+func (p *Program) RecordNames() {
+	p.varDefs = make(map[value.Value]lexer.Token)
+}
+
+// defineName records the name a variable is defined by
+func (p *Program) defineName(tok lexer.Token, ptr value.Value) {
+	if p.varDefs == nil || tok.Line == 0 {
+		return
+	}
+	p.varDefs[ptr] = tok
+	p.useName(tok, ptr)
+}
+
+// useName records a name that refers to a variable
+func (p *Program) useName(tok lexer.Token, ptr value.Value) {
+	if p.varDefs == nil || tok.Line == 0 {
+		return
+	}
+	ref := NameRef{Token: tok, Def: p.varDefs[ptr]}
+	if ptrType, isPtr := ptr.Type().(*types.PointerType); isPtr {
+		ref.Type = p.typeInfoName(ptrType.ElemType)
+	}
+	p.Names = append(p.Names, ref)
+}
@@ -16,6 +16,7 @@ func (p *Parser) parseBinaryOpRHS(exprPrec int, lhs Node) Node {
 		if tokenPrec < exprPrec {
 			return lhs
 		}
+		opToken := p.token
 		binOp := p.token.Value
 		p.Next()
 
@@ -33,7 +34,7 @@ func (p *Parser) parseBinaryOpRHS(exprPrec int, lhs Node) Node {
 			}
 		}
 		n := BinaryNode{}
-		n.TokenReference.Token = p.token
+		n.TokenReference.Token = opToken
 		n.NodeType = nodeBinary
 		n.OP = binOp
 		n.Left = lhs
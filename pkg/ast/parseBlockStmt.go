@@ -2,7 +2,6 @@ package ast
 
 import (
 	"github.com/geode-lang/geode/pkg/lexer"
-	"github.com/geode-lang/geode/pkg/util/log"
 )
 
 var blkidx = 0
@@ -17,45 +16,89 @@ func (p *Parser) parseBlockStmt() BlockNode {
 	for {
 		p.globTerminator()
 
-		if p.token.Is(lexer.TokReturn) {
-			blk.Nodes = append(blk.Nodes, p.parseReturnStmt())
-			continue
+		// If the block is over.
+		if p.token.Is(lexer.TokRightCurly) {
+			break
+		}
+		if p.token.Type <= 0 {
+			p.syntaxError(blk.Token, "block is missing a closing '}'\n")
 		}
 
-		if p.token.Is(lexer.TokIdent, lexer.TokType) {
-			node := p.parseExpression(true)
+		// Statements with syntax errors are skipped, so the rest of the
+		// block can be checked
+		if node := p.parseBlockStatement(); node != nil {
 			blk.Nodes = append(blk.Nodes, node)
-			continue
 		}
+	}
+	p.Next()
 
-		if p.token.Is(lexer.TokIf) {
-			blk.Nodes = append(blk.Nodes, p.parseIfStmt())
-			continue
-		}
+	blkidx++
+
+	return blk
+}
+
+// parseStatement parses a single statement inside of a block, returning
+// nil if the current token doesn't start one.
+func (p *Parser) parseStatement() Node {
+	if p.token.Is(lexer.TokReturn) {
+		return p.parseReturnStmt()
+	}
+
+	if p.token.Is(lexer.TokBreak) {
+		return p.parseBreakStmt()
+	}
+
+	if p.token.Is(lexer.TokContinue) {
+		return p.parseContinueStmt()
+	}
+
+	if p.token.Is(lexer.TokDefer) {
+		return p.parseDeferStmt()
+	}
 
+	if p.atLoopLabel() {
+		label := p.token.Value[:len(p.token.Value)-1]
+		p.Next()
 		if p.token.Is(lexer.TokWhile) {
-			blk.Nodes = append(blk.Nodes, p.parseWhileStmt())
-			continue
+			loop := p.parseWhileStmt().(WhileNode)
+			loop.Label = label
+			return loop
 		}
-
-		if p.token.Is(lexer.TokFor) {
-			blk.Nodes = append(blk.Nodes, p.parseForStmt())
-			continue
+		loop := p.parseForStmt()
+		if forIn, isForIn := loop.(ForInNode); isForIn {
+			forIn.Label = label
+			return forIn
 		}
+		forLoop := loop.(ForNode)
+		forLoop.Label = label
+		return forLoop
+	}
 
-		// If the block is over.
-		if p.token.Is(lexer.TokRightCurly) {
-			break
-		}
+	if p.atDestructure() {
+		return p.parseDestructure()
+	}
 
-		p.token.SyntaxError()
-		log.Fatal("Unknown token in block statement\n")
+	if p.token.Is(lexer.TokIdent, lexer.TokType, lexer.TokFuncDefn) {
+		return p.parseExpression(true)
 	}
-	p.Next()
 
-	blkidx++
+	if p.token.Is(lexer.TokIf) {
+		return p.parseIfStmt()
+	}
 
-	return blk
+	if p.token.Is(lexer.TokWhile) {
+		return p.parseWhileStmt()
+	}
+
+	if p.token.Is(lexer.TokFor) {
+		return p.parseForStmt()
+	}
+
+	if p.token.Is(lexer.TokMatch) {
+		return p.parseMatchStmt()
+	}
+
+	return nil
 }
 
 // forkBlockParser returns a new, forked parser that only has a subset of tokens that
@@ -70,9 +113,13 @@ func (p *Parser) forkBlockParser() *Parser {
 	// Start out at 1 level of nesting because the token is
 	nesting := 1
 
+	open := p.token
 	for nesting != 0 {
 		offset++
 		tok := p.Next()
+		if tok.Type <= 0 {
+			p.syntaxError(open, "block is missing a closing '}'\n")
+		}
 		if tok.Is(lexer.TokLeftCurly) {
 			nesting++
 		} else if tok.Is(lexer.TokRightCurly) {
@@ -0,0 +1,50 @@
+package ast
+
+import (
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
+func (p *Parser) parseBreakStmt() BreakNode {
+	p.requires(lexer.TokBreak)
+	n := BreakNode{}
+	n.TokenReference.Token = p.token
+	n.NodeType = nodeBreak
+	p.Next()
+
+	n.Label = p.parseBranchLabel()
+	return n
+}
+
+func (p *Parser) parseContinueStmt() ContinueNode {
+	p.requires(lexer.TokContinue)
+	n := ContinueNode{}
+	n.TokenReference.Token = p.token
+	n.NodeType = nodeContinue
+	p.Next()
+
+	n.Label = p.parseBranchLabel()
+	return n
+}
+
+// parseBranchLabel parses the optional label after a break or continue
+func (p *Parser) parseBranchLabel() string {
+	label := ""
+	if p.token.Is(lexer.TokIdent) {
+		label = p.token.Value
+		p.Next()
+	}
+	p.globTerminator()
+	return label
+}
+
+// atLoopLabel reports whether the parser is at a label in front of a loop,
+// ex: `outer: for ...`. The lexer reads the colon as part of the name.
+func (p *Parser) atLoopLabel() bool {
+	if !p.token.Is(lexer.TokIdent) || len(p.token.Value) < 2 {
+		return false
+	}
+	if p.token.Value[len(p.token.Value)-1] != ':' {
+		return false
+	}
+	return p.Peek(1).Is(lexer.TokFor, lexer.TokWhile)
+}
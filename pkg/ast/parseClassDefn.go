@@ -1,10 +1,7 @@
 package ast
 
 import (
-	"strings"
-
 	"github.com/geode-lang/geode/pkg/lexer"
-	"github.com/geode-lang/geode/pkg/util/log"
 )
 
 func (p *Parser) parseClassDefn() Node {
@@ -15,15 +12,49 @@ func (p *Parser) parseClassDefn() Node {
 
 	p.Next()
 
-	if !p.token.Is(lexer.TokType) {
-		p.token.SyntaxError()
-		log.Fatal("Class names must be capitalized. Use %q instead\n", strings.Title(p.token.Value))
-	}
+	p.requireTypeName("Class names")
 	n.Name = p.token.Value
 
 	p.Context().ClassNames[n.Name] = p.token
 
 	p.Next()
+
+	// Type parameters make the class generic, ex: `class Map<K, V>`
+	if p.token.Is(lexer.TokOper) && p.token.Value == "<" {
+		p.Next()
+		for {
+			p.requireTypeName("Type parameters")
+			n.TypeParams = append(n.TypeParams, p.token.Value)
+			p.Next()
+
+			if p.token.Is(lexer.TokComma) {
+				p.Next()
+				continue
+			}
+			if !p.parseCloseAngle() {
+				p.syntaxError(p.token, "type parameter list is missing a closing '>'\n")
+			}
+			break
+		}
+	}
+
+	// Constructor parameters name the fields they initialize, ex: `class Foo(a, b)`
+	if p.token.Is(lexer.TokLeftParen) {
+		p.Next()
+		for !p.token.Is(lexer.TokRightParen) {
+			if !p.token.Is(lexer.TokIdent) {
+				p.syntaxError(p.token, "class constructor parameters must be field names\n")
+			}
+			n.Params = append(n.Params, p.token.Value)
+			p.Next()
+
+			if p.token.Is(lexer.TokComma) {
+				p.Next()
+			}
+		}
+		p.Next()
+	}
+
 	nodes := p.parseClassBody()
 	n.Variables = make([]VariableDefnNode, 0)
 	n.Methods = make([]FunctionNode, 0)
@@ -52,13 +83,7 @@ func (p *Parser) parseClassBody() []Node {
 	p.Next()
 
 	for {
-		if p.token.Is(lexer.TokFuncDefn) {
-			fn := p.parseFunctionNode()
-			fn.IsMethod = true
-			nodes = append(nodes, fn)
-			continue
-		}
-
+		// Fields can have closure types, so types are checked for before methods
 		if p.atType() {
 			// No initializer is allowed in class variable defns
 			nodes = append(nodes, p.parseVariableDefn(false))
@@ -66,10 +91,20 @@ func (p *Parser) parseClassBody() []Node {
 			continue
 		}
 
+		if p.token.Is(lexer.TokFuncDefn) {
+			fn := p.parseFunctionNode()
+			fn.IsMethod = true
+			nodes = append(nodes, fn)
+			continue
+		}
+
 		// If the block is over.
 		if p.token.Is(lexer.TokRightCurly) {
 			break
 		}
+
+		// Anything else, like the end of the file before the class is closed
+		p.syntaxError(p.token, "expected a field, a method or '}' in the body of a class\n")
 	}
 	p.Next()
 
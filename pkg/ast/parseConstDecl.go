@@ -0,0 +1,40 @@
+package ast
+
+import (
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
+// parseConstDecl parses a constant declaration, like `const SIZE = 64 * 1024` or
+// `const byte MASK = 0x7f`
+func (p *Parser) parseConstDecl() ConstNode {
+	p.requires(lexer.TokConst)
+	n := ConstNode{}
+	n.NodeType = nodeConst
+	n.Token = p.token
+	p.Next()
+
+	// The name of a constant is often capitalized, so it is lexed as a type.
+	// If it isn't followed by the '=', it's the type of the constant instead
+	if !p.Peek(1).Is(lexer.TokOper) || p.Peek(1).Value != "=" {
+		if !p.token.Is(lexer.TokType) {
+			p.syntaxError(p.token, "expected the name or type of a constant\n")
+		}
+		typ := p.parseType()
+		n.Type = &typ
+	}
+
+	if !p.token.Is(lexer.TokIdent, lexer.TokType) {
+		p.syntaxError(p.token, "expected the name of a constant\n")
+	}
+	n.Name = p.token.Value
+	p.Next()
+
+	if !p.token.Is(lexer.TokOper) || p.token.Value != "=" {
+		p.syntaxError(p.token, "constant %s must be given a value\n", n.Name)
+	}
+	p.Next()
+
+	n.Value = p.parseExpression(false)
+	p.globTerminator()
+	return n
+}
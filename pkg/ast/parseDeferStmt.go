@@ -0,0 +1,24 @@
+package ast
+
+import (
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
+func (p *Parser) parseDeferStmt() DeferNode {
+	p.requires(lexer.TokDefer)
+	n := DeferNode{}
+	n.TokenReference.Token = p.token
+	n.NodeType = nodeDefer
+	p.Next()
+
+	if p.token.Is(lexer.TokLeftCurly) {
+		n.Body = p.parseBlockStmt()
+		return n
+	}
+
+	n.Body = p.parseExpression(false)
+	if n.Body == nil {
+		p.syntaxError(p.token, "defer must be followed by an expression or a block\n")
+	}
+	return n
+}
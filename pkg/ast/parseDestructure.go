@@ -0,0 +1,46 @@
+package ast
+
+import (
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
+// atDestructure reports whether the parser is at a list of names that values are
+// assigned to, ex: `q, r := ...`
+func (p *Parser) atDestructure() bool {
+	return p.token.Is(lexer.TokIdent) && p.Peek(1).Is(lexer.TokComma)
+}
+
+func (p *Parser) parseDestructure() DestructureNode {
+	n := DestructureNode{}
+	n.TokenReference.Token = p.token
+	n.NodeType = nodeDestructure
+	n.Names = make([]IdentNode, 0)
+
+	for {
+		if !p.token.Is(lexer.TokIdent) {
+			p.syntaxError(p.token, "expected a name to assign to\n")
+		}
+		name := NewIdentNode(p.token.Value)
+		name.TokenReference.Token = p.token
+		name.NodeType = nodeIdent
+		n.Names = append(n.Names, name)
+		p.Next()
+
+		if !p.token.Is(lexer.TokComma) {
+			break
+		}
+		p.Next()
+	}
+
+	if !p.token.Is(lexer.TokOper) || (p.token.Value != ":=" && p.token.Value != "=") {
+		p.syntaxError(p.token, "expected '=' or ':=' after the names being assigned to\n")
+	}
+	n.Declare = p.token.Value == ":="
+	p.Next()
+
+	n.Value = p.parseExpression(false)
+	if n.Value == nil {
+		p.syntaxError(p.token, "expected a value to assign\n")
+	}
+	return n
+}
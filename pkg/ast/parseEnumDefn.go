@@ -0,0 +1,66 @@
+package ast
+
+import (
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
+func (p *Parser) parseEnumDefn() Node {
+	p.requires(lexer.TokEnumDefn)
+	n := EnumNode{}
+	n.TokenReference.Token = p.token
+	n.NodeType = nodeEnum
+
+	p.Next()
+
+	p.requireTypeName("Enum names")
+	n.Name = p.token.Value
+
+	p.Next()
+	p.requires(lexer.TokLeftCurly)
+	p.Next()
+
+	n.Variants = make([]EnumVariantNode, 0)
+
+	for !p.token.Is(lexer.TokRightCurly) {
+		n.Variants = append(n.Variants, p.parseEnumVariant())
+
+		if p.token.Is(lexer.TokComma, lexer.TokSemiColon) {
+			p.Next()
+		}
+	}
+	p.Next()
+
+	return n
+}
+
+// parseEnumVariant parses a variant and the fields it carries, example: `Rect(float w, float h)`
+func (p *Parser) parseEnumVariant() EnumVariantNode {
+	v := EnumVariantNode{}
+	v.TokenReference.Token = p.token
+
+	p.requireTypeName("Enum variant names")
+	v.Name = p.token.Value
+	p.Next()
+
+	if !p.token.Is(lexer.TokLeftParen) {
+		return v
+	}
+	p.Next()
+
+	for !p.token.Is(lexer.TokRightParen) {
+		v.Types = append(v.Types, p.parseType())
+
+		if !p.token.Is(lexer.TokIdent) {
+			p.syntaxError(p.token, "The fields of an enum variant must be named\n")
+		}
+		v.Fields = append(v.Fields, p.token.Value)
+		p.Next()
+
+		if p.token.Is(lexer.TokComma) {
+			p.Next()
+		}
+	}
+	p.Next()
+
+	return v
+}
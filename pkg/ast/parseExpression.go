@@ -1,6 +1,9 @@
 package ast
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/geode-lang/geode/pkg/lexer"
 )
 
@@ -39,8 +42,12 @@ func (p *Parser) parseCompoundExpression(allowdecl bool) (ExpComponent, error) {
 		err = p.parseBooleanComponent(chain)
 	case lexer.TokChar:
 		err = p.parseCharComponent(chain)
+	case lexer.TokNil:
+		err = p.parseNilComponent(chain)
 	case lexer.TokInfo:
 		err = p.parseTypeInfoComponent(chain)
+	case lexer.TokFuncDefn:
+		err = p.parseClosureComponent(chain, allowdecl)
 	default:
 		return nil, p.Errorf("Failed to parse expression: %s", p.token.FileInfo())
 	}
@@ -90,6 +97,20 @@ func (p *Parser) parseIdentifierComponent(base *BaseComponent, allowdecl bool) e
 		return err
 	}
 	n.Value = name
+
+	// Generic classes can be constructed with their type arguments, ex: `List<int>()`
+	if n.token.Is(lexer.TokType) && p.token.Is(lexer.TokOper) && p.token.Value == "<" {
+		fork := p.Fork()
+		if fork.atTypeArguments() {
+			n.Generics = fork.parseTypeArguments()
+			if fork.token.Is(lexer.TokLeftParen) {
+				p.Join(fork)
+			} else {
+				n.Generics = nil
+			}
+		}
+	}
+
 	base.Add(n)
 
 	fork := p.Fork()
@@ -108,7 +129,7 @@ func (p *Parser) parseIdentDeclComponent(base *BaseComponent) error {
 	n := &IdentDeclComponent{}
 	n.token = p.token
 
-	if !p.token.Is(lexer.TokType) {
+	if !p.token.Is(lexer.TokType) && !p.atType() {
 		return p.Errorf("parser not at type")
 	}
 
@@ -120,6 +141,7 @@ func (p *Parser) parseIdentDeclComponent(base *BaseComponent) error {
 
 	// we now know the token is an ident, so we pull the value from it.
 	n.Name.Value = p.token.Value
+	n.Name.Token = p.token
 	p.Next()
 
 	base.Add(n)
@@ -197,6 +219,107 @@ func (p *Parser) parseArrayComponent(base *BaseComponent) error {
 	return nil
 }
 
+// =========================== parseSliceComponent ===========================
+
+// parseSliceComponent parses the rest of a slice expression after it's lower
+// bound, ex: the `:3]` in `a[1:3]`. Either of the bounds can be left out.
+func (p *Parser) parseSliceComponent(base *BaseComponent, tok lexer.Token, low Node) error {
+	n := &SliceComponent{}
+	n.token = tok
+	n.Low = low
+	p.Next()
+
+	if !p.token.Is(lexer.TokRightBrace) {
+		n.High = p.parseExpression(false)
+	}
+
+	if !p.token.Is(lexer.TokRightBrace) {
+		p.syntaxError(p.token, "expected ']' to close the slice expression\n")
+	}
+	p.Next()
+	base.Add(n)
+
+	fork := p.Fork()
+	err := fork.parseOperatorComponent(base)
+	if err == nil {
+		p.Join(fork)
+	}
+
+	return nil
+}
+
+// splitSliceBounds splits up the names in a subscript that the lexer read as
+// namespaced names, like the `i:j` in `a[i:j]`. Names that are called are left
+// alone, so `a[str:len(s)]` still calls str:len.
+func (p *Parser) splitSliceBounds() {
+	depth := 0
+	var tokens []lexer.Token
+	for i := p.tokenIndex; i < len(p.tokens); i++ {
+		tok := p.tokens[i]
+		if tok.Is(lexer.TokLeftBrace, lexer.TokLeftParen) {
+			depth++
+		} else if tok.Is(lexer.TokRightBrace, lexer.TokRightParen) {
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+
+		if depth != 1 || !tok.Is(lexer.TokIdent, lexer.TokType) || !strings.Contains(tok.Value, ":") {
+			continue
+		}
+		if i+1 < len(p.tokens) && p.tokens[i+1].Is(lexer.TokLeftParen) {
+			continue
+		}
+
+		// Forks share their token slice with the parent, so we copy it before
+		// changing it so a failed fork doesn't change the parent's tokens
+		if tokens == nil {
+			tokens = make([]lexer.Token, len(p.tokens))
+			copy(tokens, p.tokens)
+		}
+		parts := splitNameToken(tok)
+		tokens = append(tokens[:i], append(parts, tokens[i+1:]...)...)
+		i += len(parts) - 1
+	}
+
+	if tokens != nil {
+		p.tokens = tokens
+	}
+}
+
+// splitNameToken splits a namespaced name into the names on either side
+// of it's colon, and the colon itself
+func splitNameToken(tok lexer.Token) []lexer.Token {
+	idx := strings.Index(tok.Value, ":")
+
+	part := func(value string, offset int, typ lexer.TokenType) lexer.Token {
+		t := tok
+		t.Value = value
+		t.Type = typ
+		t.Pos += offset
+		t.Column += offset
+		t.EndPos = t.Pos + len(value)
+		return t
+	}
+
+	name := func(value string, offset int) lexer.Token {
+		if unicode.IsUpper([]rune(value)[0]) {
+			return part(value, offset, lexer.TokType)
+		}
+		return part(value, offset, lexer.TokIdent)
+	}
+
+	parts := []lexer.Token{
+		name(tok.Value[:idx], 0),
+		part(":", idx, lexer.TokNamespaceAccess),
+	}
+	if idx+1 < len(tok.Value) {
+		parts = append(parts, name(tok.Value[idx+1:], idx+1))
+	}
+	return parts
+}
+
 // =========================== parseNumberComponent ===========================
 
 func (p *Parser) parseNumberComponent(base *BaseComponent) error {
@@ -216,9 +339,17 @@ func (p *Parser) parseSubscriptComponent(base *BaseComponent) error {
 	n.token = p.token
 	var err error
 
+	p.splitSliceBounds()
 	p.Next()
 
-	n.Value = p.parseExpression(false)
+	// Slice expressions don't need a lower bound, ex: `a[:n]`
+	if !p.token.Is(lexer.TokNamespaceAccess) {
+		n.Value = p.parseExpression(false)
+	}
+
+	if p.token.Is(lexer.TokNamespaceAccess) {
+		return p.parseSliceComponent(base, n.token, n.Value)
+	}
 
 	if !p.token.Is(lexer.TokRightBrace) {
 		return p.Errorf("malformed array subscript %s", p.token.FileInfo())
@@ -262,6 +393,9 @@ func (p *Parser) parseStringComponent(base *BaseComponent) error {
 	n.token = p.token
 
 	n.Value = p.token.Value
+	if _, err := UnescapeString(n.Value[1 : len(n.Value)-1]); err != nil {
+		p.syntaxError(p.token, "%s", err)
+	}
 	p.Next()
 	base.Add(n)
 
@@ -289,7 +423,6 @@ func (p *Parser) parseParenthesisComponent(base *BaseComponent) error {
 	n.Value = p.parseExpression(false)
 
 	if !p.token.Is(lexer.TokRightParen) {
-		p.token.SyntaxError()
 		return p.Errorf("invalid parenthesis syntax")
 	}
 
@@ -319,6 +452,23 @@ func (p *Parser) parseBooleanComponent(base *BaseComponent) error {
 	return nil
 }
 
+// =========================== parseNilComponent ===========================
+
+func (p *Parser) parseNilComponent(base *BaseComponent) error {
+	n := &NilComponent{}
+	n.token = p.token
+
+	if !p.token.Is(lexer.TokNil) {
+		return p.Errorf("parseNilComponent expects nil")
+	}
+
+	p.Next()
+
+	base.Add(n)
+
+	return nil
+}
+
 // =========================== parseCharComponent ===========================
 
 func (p *Parser) parseCharComponent(base *BaseComponent) error {
@@ -368,3 +518,51 @@ func (p *Parser) parseTypeInfoComponent(base *BaseComponent) error {
 
 	return nil
 }
+
+// =========================== parseClosureComponent ===========================
+
+// parseClosureComponent parses closure expressions, ex: `func(int a) int = a + n`. Like
+// identifiers, a closure type could also be the start of a variable declaration.
+func (p *Parser) parseClosureComponent(base *BaseComponent, allowdecl bool) error {
+
+	fk := p.Fork()
+
+	if allowdecl && fk.parseIdentDeclComponent(base) == nil {
+		p.Join(fk)
+		return nil
+	}
+
+	n := &ClosureComponent{}
+	n.token = p.token
+
+	fn := FunctionNode{}
+	fn.TokenReference.Token = p.token
+	fn.NodeType = nodeFunction
+	fn.DeclKeyword = DeclKeywordFunc
+
+	p.Next()
+
+	if !p.token.Is(lexer.TokLeftParen) {
+		return p.Errorf("closures must have an argument list")
+	}
+	p.parseFunctionSignature(&fn)
+
+	if p.token.Is(lexer.TokLeftCurly) {
+		fn.Body = p.parseBlockStmt()
+	} else if p.token.Is(lexer.TokOper) && p.token.Value == "=" {
+		p.parseImplicitReturn(&fn)
+	} else {
+		return p.Errorf("closures must have a body")
+	}
+
+	n.Func = fn
+	base.Add(n)
+
+	fork := p.Fork()
+	err := fork.parseOperatorComponent(base)
+	if err == nil {
+		p.Join(fork)
+	}
+
+	return nil
+}
@@ -8,13 +8,20 @@ var forStmtIndex = 0
 
 func (p *Parser) parseForStmt() Node {
 	p.requires(lexer.TokFor)
-	n := ForNode{}
-	n.TokenReference.Token = p.token
-	n.NodeType = nodeFor
-	n.Index = forStmtIndex
+	tok := p.token
+	index := forStmtIndex
 	forStmtIndex++
 	p.Next()
 
+	if p.atForIn() {
+		return p.parseForInStmt(tok, index)
+	}
+
+	n := ForNode{}
+	n.TokenReference.Token = tok
+	n.NodeType = nodeFor
+	n.Index = index
+
 	n.Init = p.parseExpression(true)
 	n.Cond = p.parseExpression(false)
 	n.Step = p.parseExpression(false)
@@ -22,3 +29,43 @@ func (p *Parser) parseForStmt() Node {
 
 	return n
 }
+
+// atForIn reports whether the parser is at the names of a for-in loop,
+// ex: `x in` or `i, x in`
+func (p *Parser) atForIn() bool {
+	if !p.token.Is(lexer.TokIdent) {
+		return false
+	}
+	if p.Peek(1).Is(lexer.TokIn) {
+		return true
+	}
+	return p.Peek(1).Is(lexer.TokComma) && p.Peek(2).Is(lexer.TokIdent) && p.Peek(3).Is(lexer.TokIn)
+}
+
+// parseForInStmt parses the rest of a for-in loop, example: `for i, x in nums { ... }`
+func (p *Parser) parseForInStmt(tok lexer.Token, index int) Node {
+	n := ForInNode{}
+	n.TokenReference.Token = tok
+	n.NodeType = nodeForIn
+	n.Index = index
+
+	n.Value = p.token.Value
+	p.Next()
+	if p.token.Is(lexer.TokComma) {
+		p.Next()
+		n.Key = n.Value
+		n.Value = p.token.Value
+		p.Next()
+	}
+	p.requires(lexer.TokIn)
+	p.Next()
+
+	n.Collection = p.parseExpression(false)
+	if n.Collection == nil {
+		p.syntaxError(p.token, "expected something to loop over after 'in'\n")
+	}
+	p.requires(lexer.TokLeftCurly)
+	n.Body = p.parseBlockStmt()
+
+	return n
+}
@@ -23,38 +23,110 @@ func (p *Parser) parseFunctionNode() FunctionNode {
 		fn.DeclKeyword = DeclKeywordPure
 	}
 
+	p.parseFunctionHeader(&fn)
+
+	// fmt.Println(p.token.Value)
+
+	if p.token.Is(lexer.TokLeftCurly) {
+		fn.BodyParser = p.forkBlockParser()
+		fn.Body = fn.BodyParser.parseBlockStmt()
+		fn.BodyTokens = fn.BodyParser.tokens
+	} else if p.token.Is(lexer.TokRightArrow, lexer.TokOper) {
+
+		if p.token.Is(lexer.TokOper) && p.token.Value != "=" {
+			p.syntaxError(p.token, "unexpected token %q in function declaration\n", p.token.Value)
+		}
+
+		if p.token.Is(lexer.TokRightArrow) {
+			log.Deprecated("Use of an arrow function will be removed. Replace '->' with '=' (%s)\n", p.token.FileInfo())
+		}
+		p.parseImplicitReturn(&fn)
+		p.globTerminator()
+	} else if p.token.Is(lexer.TokElipsis) {
+		fn.External = true
+		// External functions should not be mangled
+		fn.Nomangle = true
+		p.Next()
+	} else {
+		p.syntaxError(p.token, "function %s must have a body, or end with '...' if it is external\n", fn.Name)
+	}
+
+	for _, arg := range fn.Args {
+		if arg.Type.Unknown {
+			fn.HasUnknownType = true
+		}
+	}
+
+	return fn
+}
+
+// parseImplicitReturn parses the body of a function that is declared with an
+// expression, ex: `func square(int x) int = x * x;`
+func (p *Parser) parseImplicitReturn(fn *FunctionNode) {
+	fn.Body = BlockNode{}
+	fn.Body.NodeType = nodeBlock
+	fn.Body.Nodes = make([]Node, 0)
+	fn.ImplicitReturn = true
+	p.Next()
+	start := p.tokenIndex
+
+	// Only functions that return more than one value take a list, so closures
+	// in argument lists don't swallow the arguments after them
+	var implReturnValue Node
+	if len(fn.ReturnType.Tuple) > 0 {
+		implReturnValue = p.parseReturnValues()
+	} else {
+		implReturnValue = p.parseExpression(false)
+	}
+	implReturn := ReturnNode{}
+	implReturn.Value = implReturnValue
+	fn.Body.Nodes = []Node{implReturn}
+	fn.BodyTokens = p.tokens[start:p.tokenIndex]
+}
+
+// parseFunctionHeader parses the name, arguments and return type of a function
+// declaration into fn. It leaves the parser at the start of the function body.
+func (p *Parser) parseFunctionHeader(fn *FunctionNode) {
 	if p.token.Type == lexer.TokIdent && p.token.Value == "nomangle" {
 		fn.Nomangle = true
 		p.Next()
 	}
 
+	nameToken := p.token
 	rawNameString, _ := p.parseName()
 	fn.Name = NewIdentNode(rawNameString)
+	fn.Name.Token = nameToken
 
 	// The main function should never be mangled
 	if rawNameString == "main" {
 		fn.Nomangle = true
 	}
 
+	p.parseFunctionSignature(fn)
+}
+
+// parseFunctionSignature parses the arguments and return type of a function into fn.
+// Closure expressions have a signature without a name in front of it.
+func (p *Parser) parseFunctionSignature(fn *FunctionNode) {
 	if p.token.Type == lexer.TokLeftParen {
 		p.Next()
 
 		for {
 
 			// Parse a function argument
-			if p.token.Is(lexer.TokIdent, lexer.TokType) {
+			if p.token.Is(lexer.TokIdent, lexer.TokType, lexer.TokFuncDefn) {
 
 				typ := p.parseType()
 
 				if !p.token.Is(lexer.TokIdent) {
-					p.token.SyntaxError()
-					log.Fatal("invalid function argument\n")
+					p.syntaxError(p.token, "invalid function argument\n")
 				}
 
 				for p.token.Is(lexer.TokIdent) {
 					arg := FunctionArg{}
 					arg.Type = typ
 					arg.Name = p.token.Value
+					arg.Token = p.token
 					p.Next()
 					fn.Args = append(fn.Args, arg)
 				}
@@ -79,60 +151,22 @@ func (p *Parser) parseFunctionNode() FunctionNode {
 				p.Next()
 				continue
 			}
+
+			p.syntaxError(p.token, "expected an argument, ',' or ')' in the argument list\n")
 		}
 
 	}
 
-	if p.token.Is(lexer.TokType) {
+	if p.token.Is(lexer.TokType) || (p.token.Is(lexer.TokFuncDefn) && p.Peek(1).Is(lexer.TokLeftParen)) {
 		fn.ReturnType = p.parseType()
+	} else if p.token.Is(lexer.TokLeftParen) {
+		fn.ReturnType = p.parseTupleType()
 	} else {
 		fn.ReturnType = TypeNode{}
 		fn.ReturnType.Name = "void"
 		fn.ReturnType.PointerLevel = 0
 		fn.ReturnType.Unknown = false
 	}
-	// fmt.Println(p.token.Value)
-
-	if p.token.Is(lexer.TokLeftCurly) {
-		fn.BodyParser = p.forkBlockParser()
-	} else if p.token.Is(lexer.TokRightArrow, lexer.TokOper) {
-
-		if p.token.Is(lexer.TokOper) && p.token.Value != "=" {
-			p.token.SyntaxError()
-			log.Fatal("unexpected token %q in function declaration\n", p.token.Value)
-		}
-
-		if p.token.Is(lexer.TokRightArrow) {
-			log.Deprecated("Use of an arrow function will be removed. Replace '->' with '=' (%s)\n", p.token.FileInfo())
-		}
-		fn.Body = BlockNode{}
-		fn.Body.NodeType = nodeBlock
-		fn.Body.Nodes = make([]Node, 0)
-		fn.ImplicitReturn = true
-		p.Next()
-
-		implReturnValue := p.parseExpression(false)
-		implReturn := ReturnNode{}
-		implReturn.Value = implReturnValue
-		fn.Body.Nodes = []Node{implReturn}
-		p.globTerminator()
-	} else if p.token.Is(lexer.TokElipsis) {
-		fn.External = true
-		// External functions should not be mangled
-		fn.Nomangle = true
-		p.Next()
-	} else {
-		p.token.SyntaxError()
-		log.Fatal("")
-	}
-
-	for _, arg := range fn.Args {
-		if arg.Type.Unknown {
-			fn.HasUnknownType = true
-		}
-	}
-
-	return fn
 }
 
 // QuickParseFunction takes a stream of tokens and lexes them into a single node
@@ -2,7 +2,6 @@ package ast
 
 import (
 	"github.com/geode-lang/geode/pkg/lexer"
-	"github.com/geode-lang/geode/pkg/util/log"
 )
 
 func (p *Parser) parseGlobalVariableDecl() GlobalVariableDeclNode {
@@ -16,17 +15,16 @@ func (p *Parser) parseGlobalVariableDecl() GlobalVariableDeclNode {
 
 		if p.token.Is(lexer.TokIdent) {
 			n.Name = NewIdentNode(p.token.Value)
+			n.Name.Token = p.token
 			p.Next()
 		} else if p.token.Is(lexer.TokOper) && p.token.Value == "=" {
 
 		} else {
-			n.SyntaxError()
-			log.Fatal("Invalid Global variable declaration\n")
+			p.syntaxError(n.Token, "Invalid Global variable declaration\n")
 		}
 
 	} else {
-		p.token.SyntaxError()
-		log.Fatal("Invalid Global variable declaration\n")
+		p.syntaxError(p.token, "Invalid Global variable declaration\n")
 	}
 
 	if p.token.Is(lexer.TokOper) && p.token.Value == "=" {
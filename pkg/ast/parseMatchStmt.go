@@ -0,0 +1,74 @@
+package ast
+
+import (
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
+var matchStmtIndex = 0
+
+func (p *Parser) parseMatchStmt() Node {
+	p.requires(lexer.TokMatch)
+	n := MatchNode{}
+	n.TokenReference.Token = p.token
+	n.NodeType = nodeMatch
+	n.Index = matchStmtIndex
+	matchStmtIndex++
+	p.Next()
+
+	n.Value = p.parseExpression(false)
+	p.requires(lexer.TokLeftCurly)
+	p.Next()
+
+	for {
+		p.globTerminator()
+
+		if p.token.Is(lexer.TokRightCurly) {
+			break
+		}
+
+		n.Cases = append(n.Cases, p.parseMatchCase())
+	}
+	p.Next()
+
+	return n
+}
+
+// parseMatchCase parses a single case of a match statement, example: `1, 2 -> stmt;`
+func (p *Parser) parseMatchCase() MatchCase {
+	c := MatchCase{}
+	c.TokenReference.Token = p.token
+
+	for {
+		if p.token.Is(lexer.TokIdent) && p.token.Value == "_" {
+			c.Default = true
+			p.Next()
+		} else {
+			c.Patterns = append(c.Patterns, p.parseExpression(false))
+		}
+
+		if !p.token.Is(lexer.TokComma) {
+			break
+		}
+		p.Next()
+	}
+
+	p.requires(lexer.TokRightArrow)
+	p.Next()
+
+	// The body of a case is either a block or a single statement
+	if p.token.Is(lexer.TokLeftCurly) {
+		c.Body = p.parseBlockStmt()
+		return c
+	}
+
+	stmt := p.parseStatement()
+	if stmt == nil {
+		p.syntaxError(p.token, "Unknown token in match case\n")
+	}
+	blk := BlockNode{}
+	blk.TokenReference.Token = c.Token
+	blk.NodeType = nodeBlock
+	blk.Nodes = []Node{stmt}
+	c.Body = blk
+	return c
+}
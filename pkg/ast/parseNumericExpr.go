@@ -40,11 +40,12 @@ func GetNumberNodeFromString(str string) (Node, error) {
 		}
 		n := IntNode{}
 		n.NodeType = nodeInt
-		parsed, e := strconv.ParseInt(strings.TrimPrefix(str, "0x"), 16, 64)
+		// Hex literals can use all 64 bits, for unsigned values like `0xcbf29ce484222325`
+		parsed, e := strconv.ParseUint(strings.TrimPrefix(str, "0x"), 16, 64)
 		if e != nil {
 			return nil, fmt.Errorf("error decoding hex token")
 		}
-		n.Value = parsed
+		n.Value = int64(parsed)
 		return n, nil
 	}
 
@@ -55,11 +56,11 @@ func GetNumberNodeFromString(str string) (Node, error) {
 		} else {
 			n := IntNode{}
 			n.NodeType = nodeInt
-			parsed, e := strconv.ParseInt(strings.TrimPrefix(str, "0b"), 2, 64)
+			parsed, e := strconv.ParseUint(strings.TrimPrefix(str, "0b"), 2, 64)
 			if e != nil {
 				return nil, fmt.Errorf("error decoding binary token")
 			}
-			n.Value = parsed
+			n.Value = int64(parsed)
 			return n, nil
 		}
 	}
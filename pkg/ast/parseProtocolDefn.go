@@ -0,0 +1,48 @@
+package ast
+
+import (
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
+func (p *Parser) parseProtocolDefn() Node {
+	p.requires(lexer.TokProtocolDefn)
+	n := ProtocolNode{}
+	n.TokenReference.Token = p.token
+	n.NodeType = nodeProtocol
+
+	p.Next()
+
+	p.requireTypeName("Protocol names")
+	n.Name = p.token.Value
+
+	p.Next()
+	p.requires(lexer.TokLeftCurly)
+	p.Next()
+
+	n.Methods = make([]FunctionNode, 0)
+
+	for !p.token.Is(lexer.TokRightCurly) {
+		if !p.token.Is(lexer.TokFuncDefn) {
+			p.syntaxError(p.token, "protocols may only contain method declarations\n")
+		}
+
+		fn := FunctionNode{}
+		fn.TokenReference.Token = p.token
+		fn.NodeType = nodeFunction
+		fn.DeclKeyword = DeclKeywordFunc
+		fn.IsMethod = true
+		p.Next()
+
+		p.parseFunctionHeader(&fn)
+
+		if !p.token.Is(lexer.TokSemiColon) {
+			p.syntaxError(p.token, "protocol methods cannot have a body\n")
+		}
+		p.Next()
+
+		n.Methods = append(n.Methods, fn)
+	}
+	p.Next()
+
+	return n
+}
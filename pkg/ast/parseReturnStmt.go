@@ -1,12 +1,40 @@
 package ast
 
+import (
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
 func (p *Parser) parseReturnStmt() ReturnNode {
 	n := ReturnNode{}
 	n.TokenReference.Token = p.token
 	p.Next()
 
-	n.Value = p.parseExpression(false)
+	n.Value = p.parseReturnValues()
 
 	p.globTerminator()
 	return n
 }
+
+// parseReturnValues parses the value a function returns. More than one value
+// is parsed into a tuple, ex: `return q, r;`
+func (p *Parser) parseReturnValues() Node {
+	tok := p.token
+	value := p.parseExpression(false)
+	if value == nil || !p.token.Is(lexer.TokComma) {
+		return value
+	}
+
+	n := TupleNode{}
+	n.TokenReference.Token = tok
+	n.NodeType = nodeTuple
+	n.Values = []Node{value}
+	for p.token.Is(lexer.TokComma) {
+		p.Next()
+		value := p.parseExpression(false)
+		if value == nil {
+			p.syntaxError(p.token, "expected a value after ','\n")
+		}
+		n.Values = append(n.Values, value)
+	}
+	return n
+}
@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 
-	"github.com/geode-lang/geode/pkg/util/log"
+	"fmt"
 )
 
 const (
@@ -60,9 +60,12 @@ func UnescapeString(s string) (string, error) {
 				continue
 			}
 
+			if i >= len(sr) {
+				return "", fmt.Errorf("string ends with an unfinished escape")
+			}
 			esc, ok := escapes[sr[i]]
 			if !ok {
-				log.Fatal("Unknown escape: '\\%c'\n", sr[i])
+				return "", fmt.Errorf("Unknown escape: '\\%c'", sr[i])
 			}
 			buff.WriteRune(esc)
 		} else {
@@ -1,8 +1,9 @@
 package ast
 
 import (
+	"strings"
+
 	"github.com/geode-lang/geode/pkg/lexer"
-	"github.com/geode-lang/geode/pkg/util/log"
 )
 
 var typeOperators = []string{"*", "?"}
@@ -18,13 +19,71 @@ func validTypeInfoTokens(t lexer.Token) bool {
 }
 
 func (p *Parser) atType() bool {
+	// Closure types are only types if they are well formed, otherwise
+	// they might be the start of a closure expression
+	if p.token.Is(lexer.TokFuncDefn) {
+		fork := p.Fork()
+		if _, err := fork.parseClosureType(); err != nil {
+			return false
+		}
+		for validTypeInfoTokens(fork.token) {
+			fork.Next()
+		}
+		return fork.token.Is(lexer.TokIdent)
+	}
+
 	if !p.token.Is(lexer.TokType) {
 		return false
 	}
 
 	offset := 1
-	for validTypeInfoTokens(p.Peek(offset)) {
-		offset++
+
+	// Skip over the type arguments of a generic type, ex: `Map<string, List<int>>`
+	if p.Peek(offset).Value == "<" {
+		depth := 0
+		for {
+			tok := p.Peek(offset)
+			if tok.Type <= 0 {
+				return false
+			}
+			depth += strings.Count(tok.Value, "<") - strings.Count(tok.Value, ">")
+			if depth <= 0 {
+				break
+			}
+			offset++
+		}
+		// The closing '>' might be lexed together with pointer operators
+		if strings.Trim(p.Peek(offset).Value, ">*") == "" {
+			offset++
+		}
+	}
+
+	for {
+		if validTypeInfoTokens(p.Peek(offset)) {
+			offset++
+		} else if p.Peek(offset).Is(lexer.TokLeftBrace) && p.Peek(offset+1).Is(lexer.TokRightBrace) {
+			offset += 2
+		} else if p.Peek(offset).Is(lexer.TokLeftBrace) {
+			// Skip over the length of a fixed size array, ex: `byte[SIZE * 2]`
+			depth := 0
+			for {
+				tok := p.Peek(offset)
+				if tok.Type <= 0 {
+					return false
+				}
+				if tok.Is(lexer.TokLeftBrace) {
+					depth++
+				} else if tok.Is(lexer.TokRightBrace) {
+					depth--
+				}
+				offset++
+				if depth == 0 {
+					break
+				}
+			}
+		} else {
+			break
+		}
 	}
 
 	if p.Peek(offset).Type == lexer.TokIdent {
@@ -34,13 +93,50 @@ func (p *Parser) atType() bool {
 	return false
 }
 
+// atTypeArguments reports whether the parser is at a list of type arguments, which
+// is only made up of types, commas and angle brackets
+func (p *Parser) atTypeArguments() bool {
+	depth := 0
+	for offset := 0; ; offset++ {
+		tok := p.Peek(offset)
+		switch {
+		case tok.Is(lexer.TokType, lexer.TokComma):
+		case tok.Is(lexer.TokOper) && strings.Trim(tok.Value, "<>*") == "":
+			depth += strings.Count(tok.Value, "<") - strings.Count(tok.Value, ">")
+			if depth <= 0 {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
 // parseType returns a
 
 func (p *Parser) parseType() (t TypeNode) {
-	p.requires(lexer.TokType)
+	if p.token.Is(lexer.TokFuncDefn) {
+		var err error
+		t, err = p.parseClosureType()
+		if err != nil {
+			p.syntaxError(p.token, "%s\n", err)
+		}
+	} else {
+		p.requires(lexer.TokType)
+
+		t.Name, _ = p.parseName()
 
-	t.Name, _ = p.parseName()
+		if p.token.Is(lexer.TokOper) && p.token.Value == "<" {
+			t.Generics = p.parseTypeArguments()
+		}
+	}
 
+	p.parseTypeModifiers(&t)
+	return t
+}
+
+// parseTypeModifiers parses the pointer, unknown, slice and array modifiers after a type
+func (p *Parser) parseTypeModifiers(t *TypeNode) {
 	t.Modifiers = make([]TypeModifier, 0)
 	// p.Next()
 
@@ -48,7 +144,7 @@ func (p *Parser) parseType() (t TypeNode) {
 
 		if p.token.Is(lexer.TokQuestionMark) {
 			if t.Unknown {
-				log.Fatal("Multiple Unknown Type operators for %q used.\n", t.Name)
+				p.syntaxError(p.token, "Multiple Unknown Type operators for %q used.\n", t.Name)
 			}
 
 			t.Unknown = true
@@ -68,17 +164,169 @@ func (p *Parser) parseType() (t TypeNode) {
 			continue
 		}
 		// handle slice type definition `T[]` for some T
-		// if p.token.Is(lexer.TokLeftBrace) {
-		// 	p.Next()
-		// 	p.requires(lexer.TokRightBrace)
-		// 	t.Modifiers = append(t.Modifiers, ModifierSlice)
-		// 	p.Next()
-		// 	continue
-		// }
+		if p.token.Is(lexer.TokLeftBrace) && p.Peek(1).Is(lexer.TokRightBrace) {
+			p.Next()
+			t.Modifiers = append(t.Modifiers, ModifierSlice)
+			p.Next()
+			continue
+		}
+		// handle fixed size array types `T[N]`, where N is a constant
+		if p.token.Is(lexer.TokLeftBrace) {
+			p.Next()
+			t.Lengths = append(t.Lengths, p.parseExpression(false))
+			if !p.token.Is(lexer.TokRightBrace) {
+				p.syntaxError(p.token, "expected ']' after the length of array type %q\n", t.Name)
+			}
+			t.Modifiers = append(t.Modifiers, ModifierArray)
+			p.Next()
+			continue
+		}
 
 		break
 
 	}
+}
+
+// parseClosureType parses the type of a closure, ex: `func(int, string) int`. Errors
+// are returned instead of being fatal, because a closure expression starts the same way.
+func (p *Parser) parseClosureType() (t TypeNode, err error) {
+	p.requires(lexer.TokFuncDefn)
+	t.Name = "func"
+	t.Closure = true
+	t.Params = make([]TypeNode, 0)
+	p.Next()
+
+	if !p.token.Is(lexer.TokLeftParen) {
+		return t, p.Errorf("expected '(' after func in closure type")
+	}
+	p.Next()
+
+	for !p.token.Is(lexer.TokRightParen) {
+		param, err := p.parseClosureTypePart()
+		if err != nil {
+			return t, err
+		}
+		t.Params = append(t.Params, param)
 
+		if p.token.Is(lexer.TokComma) {
+			p.Next()
+		} else if !p.token.Is(lexer.TokRightParen) {
+			return t, p.Errorf("expected ',' or ')' in closure type")
+		}
+	}
+	p.Next()
+
+	if p.token.Is(lexer.TokType) || (p.token.Is(lexer.TokFuncDefn) && p.Peek(1).Is(lexer.TokLeftParen)) {
+		ret, err := p.parseClosureTypePart()
+		if err != nil {
+			return t, err
+		}
+		t.Return = &ret
+	}
+
+	return t, nil
+}
+
+// parseTupleType parses the types of the values a function returns when it
+// returns more than one, ex: `(int, string)`
+func (p *Parser) parseTupleType() (t TypeNode) {
+	p.requires(lexer.TokLeftParen)
+	t.Tuple = make([]TypeNode, 0)
+	p.Next()
+
+	for !p.token.Is(lexer.TokRightParen) {
+		if !p.token.Is(lexer.TokType, lexer.TokFuncDefn) {
+			p.syntaxError(p.token, "expected a type in the list of return types\n")
+		}
+		t.Tuple = append(t.Tuple, p.parseType())
+
+		if p.token.Is(lexer.TokComma) {
+			p.Next()
+		} else if !p.token.Is(lexer.TokRightParen) {
+			p.syntaxError(p.token, "expected ',' or ')' in the list of return types\n")
+		}
+	}
+	p.Next()
+
+	if len(t.Tuple) < 2 {
+		p.syntaxError(p.token, "a list of return types must have at least two types\n")
+	}
 	return t
 }
+
+// parseClosureTypePart parses a parameter or return type of a closure type
+func (p *Parser) parseClosureTypePart() (TypeNode, error) {
+	if p.token.Is(lexer.TokType) {
+		return p.parseType(), nil
+	}
+	if !p.token.Is(lexer.TokFuncDefn) {
+		return TypeNode{}, p.Errorf("expected a type in closure type")
+	}
+	t, err := p.parseClosureType()
+	if err != nil {
+		return t, err
+	}
+	p.parseTypeModifiers(&t)
+	return t, nil
+}
+
+// parseTypeArguments parses the list of types passed to a generic type, ex: `<string, Person*>`
+func (p *Parser) parseTypeArguments() []TypeNode {
+	args := make([]TypeNode, 0)
+	p.Next()
+
+	for {
+		if !p.token.Is(lexer.TokType) {
+			p.syntaxError(p.token, "expected a type in type argument list\n")
+		}
+		args = append(args, p.parseType())
+
+		if p.token.Is(lexer.TokComma) {
+			p.Next()
+			continue
+		}
+
+		if !p.parseCloseAngle() {
+			p.syntaxError(p.token, "type argument list is missing a closing '>'\n")
+		}
+		return args
+	}
+}
+
+// parseCloseAngle consumes a single '>' that closes a list of type arguments.
+// The lexer globs operators together, so tokens like `>>` or `>*` are split up
+// and the rest of the operator is left as the current token.
+func (p *Parser) parseCloseAngle() bool {
+	if !p.token.Is(lexer.TokOper) || !strings.HasPrefix(p.token.Value, ">") {
+		return false
+	}
+
+	if p.token.Value == ">" {
+		p.Next()
+		return true
+	}
+
+	angle := p.token
+	angle.Value = ">"
+	angle.EndPos = angle.Pos + 1
+	angle.SpaceAfter = false
+
+	rest := p.token
+	rest.Value = rest.Value[1:]
+	rest.Pos++
+	rest.Column++
+	rest.Leading = ""
+	rest.SpaceBefore = false
+
+	// The operator is split in two tokens rather than changed, so parsing the
+	// same tokens again, like a function body, finds the same '>'. Forks share
+	// their token slice with the parent, so we copy it before changing it so a
+	// failed fork doesn't change the parent's tokens
+	tokens := make([]lexer.Token, 0, len(p.tokens)+1)
+	tokens = append(tokens, p.tokens[:p.tokenIndex]...)
+	tokens = append(tokens, angle, rest)
+	tokens = append(tokens, p.tokens[p.tokenIndex+1:]...)
+	p.tokens = tokens
+	p.Next()
+	return true
+}
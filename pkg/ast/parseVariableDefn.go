@@ -2,7 +2,6 @@ package ast
 
 import (
 	"github.com/geode-lang/geode/pkg/lexer"
-	"github.com/geode-lang/geode/pkg/util/log"
 )
 
 func (p *Parser) parseVariableDefn(allowDefn bool) VariableDefnNode {
@@ -14,16 +13,15 @@ func (p *Parser) parseVariableDefn(allowDefn bool) VariableDefnNode {
 	if p.atType() {
 		n.Typ = p.parseType()
 	} else {
-		p.token.SyntaxError()
-		log.Fatal("let: Invalid variable declaration\n")
+		p.syntaxError(p.token, "let: Invalid variable declaration\n")
 	}
 
 	if p.token.Is(lexer.TokIdent) {
 		n.Name = NewIdentNode(p.token.Value)
+		n.Name.Token = p.token
 		p.Next()
 	} else {
-		n.SyntaxError()
-		log.Fatal("type: Invalid variable declaration\n")
+		p.syntaxError(n.Token, "type: Invalid variable declaration\n")
 	}
 
 	if p.token.Is(lexer.TokAssignment) {
@@ -32,11 +30,10 @@ func (p *Parser) parseVariableDefn(allowDefn bool) VariableDefnNode {
 			p.Next()
 			n.Body = p.parseExpression(false)
 		} else {
-			log.Fatal("Variable Initialization of '%s' is not allowed in it's context\n", n.Name)
+			p.syntaxError(p.token, "Variable Initialization of '%s' is not allowed in it's context\n", n.Name)
 		}
 	} else if n.NeedsInference {
-		n.SyntaxError()
-		log.Fatal("When declaring a variable with let, it must have an assignment\n")
+		p.syntaxError(n.Token, "When declaring a variable with let, it must have an assignment\n")
 	}
 
 	return n
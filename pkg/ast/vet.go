@@ -0,0 +1,261 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/geode-lang/geode/pkg/diag"
+	"github.com/geode-lang/geode/pkg/lexer"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/value"
+)
+
+// VetCheck names one of the checks a program can be vetted with
+type VetCheck string
+
+// The checks programs can be vetted with
+const (
+	VetUnused        VetCheck = "unused"         // local variables and parameters that are never used
+	VetUnreachable   VetCheck = "unreachable"    // code that comes after a return, break or continue
+	VetShadow        VetCheck = "shadow"         // variables with the name of one in an outer scope
+	VetMissingReturn VetCheck = "missing-return" // functions that can end without returning a value
+	VetAssignInIf    VetCheck = "assign-in-if"   // assignments used as the condition of an if
+	VetPureResult    VetCheck = "pure-result"    // calls to pure functions that ignore the result
+)
+
+// VetChecks is every check, in the order they are listed in
+var VetChecks = []VetCheck{VetUnused, VetUnreachable, VetShadow, VetMissingReturn, VetAssignInIf, VetPureResult}
+
+// vetter has what the checks of a program found while it was compiled
+type vetter struct {
+	checks   map[VetCheck]bool
+	warnings []warning
+	seen     map[string]bool
+
+	// The local variables and parameters that were declared, by the values
+	// they are stored in. Functions compiled more than once, like methods of
+	// generic classes, declare the same local each time
+	locals []*local
+	defs   map[value.Value]*local
+	byPos  map[string]*local
+}
+
+// warning is a diagnostic, along with the path of the file it is in
+type warning struct {
+	path string
+	diag *diag.Diagnostic
+}
+
+// local is a local variable or parameter, and whether anything uses it
+type local struct {
+	tok   lexer.Token
+	param bool
+	used  bool
+}
+
+// Vet makes the program look for likely bugs with some checks as it is
+// compiled. What they find in the functions that are compiled is returned by
+// Warnings
+func (p *Program) Vet(checks ...VetCheck) {
+	p.vet = &vetter{
+		checks: make(map[VetCheck]bool),
+		seen:   make(map[string]bool),
+		defs:   make(map[value.Value]*local),
+		byPos:  make(map[string]*local),
+	}
+	for _, check := range checks {
+		p.vet.checks[check] = true
+	}
+}
+
+// vetting returns if the program is being vetted with a check
+func (p *Program) vetting(check VetCheck) bool {
+	return p.vet != nil && p.vet.checks[check]
+}
+
+// warn reports what a check found in the file at a path
+func (p *Program) warn(check VetCheck, path string, d *diag.Diagnostic) {
+	if !p.vetting(check) || d.Span == nil {
+		return
+	}
+	// Functions compiled more than once only report things once
+	key := fmt.Sprintf("%s %s", check, d.Error())
+	if p.vet.seen[key] {
+		return
+	}
+	p.vet.seen[key] = true
+	d.Note("reported by the %s check", check)
+	p.vet.warnings = append(p.vet.warnings, warning{path, d})
+}
+
+// Warnings returns what the checks found in a file, in the functions that were
+// compiled. Unused variables are only known to be unused once everything is
+// compiled, so warnings are best asked for last
+func (p *Program) Warnings(path string) diag.List {
+	list := diag.List{}
+	if p.vet == nil {
+		return list
+	}
+	for _, w := range p.vet.warnings {
+		if w.path == path {
+			list = append(list, w.diag)
+		}
+	}
+
+	if p.vetting(VetUnused) {
+		for _, l := range p.vet.locals {
+			name := l.tok.Value
+			if l.used || l.tok.Path() != path || name == "this" || strings.HasPrefix(name, "_") {
+				continue
+			}
+			kind := "variable"
+			if l.param {
+				kind = "parameter"
+			}
+			d := diag.Warningf(l.tok.Span(), "%s %s is never used", kind, name)
+			d.Suggest(l.tok.Span(), "_"+name, "if it is meant to be unused, start its name with an underscore")
+			d.Note("reported by the %s check", VetUnused)
+			list = append(list, d)
+		}
+	}
+	list.Sort()
+	return list
+}
+
+// vetDefine records the declaration of a local variable or parameter, so it can
+// be found out if it is ever used or shadows another
+func (p *Program) vetDefine(tok lexer.Token, ptr value.Value, param bool) {
+	if p.vet == nil || tok.Line == 0 {
+		return
+	}
+	key := fmt.Sprintf("%s:%d", tok.Path(), tok.Pos)
+	l, found := p.vet.byPos[key]
+	if !found {
+		l = &local{tok: tok, param: param}
+		p.vet.byPos[key] = l
+		p.vet.locals = append(p.vet.locals, l)
+	}
+	p.vet.defs[ptr] = l
+
+	// Variables are added to the scope before they are recorded, so the
+	// search starts at the parent. The root scope has the globals, which
+	// aren't shadowed by locals as much as named the same
+	for s := p.Scope.Parent; s != nil && s.Parent != nil; s = s.Parent {
+		item, found := s.Vals[tok.Value]
+		if !found {
+			continue
+		}
+		if variable, isVariable := item.(VariableScopeItem); isVariable {
+			d := diag.Warningf(tok.Span(), "%s shadows a variable in an outer scope", tok.Value)
+			if outer := p.vet.defs[variable.Value()]; outer != nil {
+				d.Label(outer.tok.Span(), "%s is first declared here", tok.Value)
+			}
+			p.warn(VetShadow, tok.Path(), d)
+		}
+		return
+	}
+}
+
+// vetUse records that a variable is used
+func (p *Program) vetUse(ptr value.Value) {
+	if p.vet == nil {
+		return
+	}
+	if l, found := p.vet.defs[ptr]; found {
+		l.used = true
+	}
+}
+
+// vetStatement checks a statement in a block, after it is compiled to val
+func (p *Program) vetStatement(node Node, val value.Value) {
+	if !p.vetting(VetPureResult) {
+		return
+	}
+	call, isCall := node.(FunctionCallNode)
+	inst, isInst := val.(*ir.InstCall)
+	if !isCall || !isInst {
+		return
+	}
+	for _, fn := range p.Functions {
+		if fn.DeclKeyword != DeclKeywordPure {
+			continue
+		}
+		for _, variant := range fn.Variants {
+			if variant == inst.Callee {
+				d := diag.Warningf(statementSpan(call), "the result of pure function %s is not used", fn.Name)
+				d.Note("pure functions do nothing but return a value, so calling one without using it has no effect")
+				p.warn(VetPureResult, call.Token.Path(), d)
+				return
+			}
+		}
+	}
+}
+
+// statementSpan returns where a statement is. Calls are at the name of what
+// they call rather than the bracket after it
+func statementSpan(node Node) diag.Span {
+	if call, isCall := node.(FunctionCallNode); isCall {
+		if name, isNode := call.Name.(Node); isNode {
+			return name.Span()
+		}
+	}
+	return node.Span()
+}
+
+// terminates returns if a statement always returns, breaks or continues, so
+// nothing after it in the block it is in can run
+func terminates(node Node) bool {
+	switch n := node.(type) {
+	case ReturnNode, BreakNode, ContinueNode:
+		return true
+	case BlockNode:
+		for _, stmt := range n.Nodes {
+			if terminates(stmt) {
+				return true
+			}
+		}
+	case IfNode:
+		return n.Else != nil && terminates(n.Then) && terminates(n.Else)
+	case MatchNode:
+		// Matches without a default case might not run any of them
+		hasDefault := false
+		for _, c := range n.Cases {
+			if !terminates(c.Body) {
+				return false
+			}
+			hasDefault = hasDefault || c.Default
+		}
+		return hasDefault
+	}
+	return false
+}
+
+// returns returns if a statement always returns from the function it is in
+func returns(node Node) bool {
+	switch n := node.(type) {
+	case ReturnNode:
+		return true
+	case BlockNode:
+		for _, stmt := range n.Nodes {
+			if returns(stmt) {
+				return true
+			}
+			// Code after a break or continue never runs
+			if terminates(stmt) {
+				return false
+			}
+		}
+	case IfNode:
+		return n.Else != nil && returns(n.Then) && returns(n.Else)
+	case MatchNode:
+		hasDefault := false
+		for _, c := range n.Cases {
+			if !returns(c.Body) {
+				return false
+			}
+			hasDefault = hasDefault || c.Default
+		}
+		return hasDefault
+	}
+	return false
+}
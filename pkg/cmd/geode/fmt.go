@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/geode-lang/geode/pkg/arg"
+	"github.com/geode-lang/geode/pkg/diag"
+	"github.com/geode-lang/geode/pkg/format"
+	"github.com/geode-lang/geode/pkg/lexer"
+	"github.com/geode-lang/geode/pkg/util/log"
+)
+
+// FormatFiles formats the Geode files at some paths, and the ones in the
+// directories among them. Formatted files are printed, unless they are written
+// back with --write or checked with --check
+func FormatFiles(paths []string) {
+	files := make([]string, 0)
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil {
+			log.Fatal("%s\n", err)
+		}
+		if !stat.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() && strings.HasSuffix(file, ".g") {
+				files = append(files, file)
+			}
+			return err
+		})
+	}
+
+	failed := false
+	unformatted := 0
+	for _, file := range files {
+		stat, err := os.Stat(file)
+		if err != nil {
+			log.Fatal("%s\n", err)
+		}
+		bytes, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Fatal("%s\n", err)
+		}
+		src, _ := lexer.NewSourcefile(file)
+		src.LoadBytes(bytes)
+
+		formatted, err := format.Source(src)
+		if err != nil {
+			diag.ListFrom(err).Render(os.Stdout)
+			failed = true
+			continue
+		}
+
+		changed := formatted != string(bytes)
+		if *arg.FmtCheck && changed {
+			fmt.Println(file)
+			unformatted++
+		}
+		if *arg.FmtWrite && changed {
+			if err := ioutil.WriteFile(file, []byte(formatted), stat.Mode()); err != nil {
+				log.Fatal("%s\n", err)
+			}
+		}
+		if !*arg.FmtCheck && !*arg.FmtWrite {
+			fmt.Print(formatted)
+		}
+	}
+
+	// Files that --write formatted aren't a failure
+	if failed || (unformatted > 0 && !*arg.FmtWrite) {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -12,10 +12,12 @@ import (
 
 	"github.com/geode-lang/geode/pkg/arg"
 	"github.com/geode-lang/geode/pkg/ast"
+	"github.com/geode-lang/geode/pkg/diag"
+	"github.com/geode-lang/geode/pkg/gtypes"
 	"github.com/geode-lang/geode/pkg/info"
+	"github.com/geode-lang/geode/pkg/lsp"
 	"github.com/geode-lang/geode/pkg/pkg"
 	"github.com/geode-lang/geode/pkg/util"
-	"github.com/geode-lang/geode/pkg/util/color"
 	"github.com/geode-lang/geode/pkg/util/log"
 )
 
@@ -55,6 +57,14 @@ func main() {
 		}
 	}
 
+	// The target defaults to the one clang compiles for
+	if *arg.TargetTriple != "" {
+		if _, err := gtypes.LookupTarget(*arg.TargetTriple); err != nil {
+			log.Fatal("%s\n", err)
+		}
+		targetTripple = *arg.TargetTriple
+	}
+
 	log.Verbose("Clang Version: %s\n", clangVersion)
 	log.Verbose("Building to %s...\n", buildDir)
 
@@ -98,6 +108,19 @@ func main() {
 			context.Build(buildDir)
 			info.DumpJSON()
 		})
+
+	case arg.FmtCMD.FullCommand():
+		FormatFiles(*arg.FmtInput)
+
+	case arg.VetCMD.FullCommand():
+		VetPackage(*arg.VetInput, targetTripple)
+
+	case arg.LSPCMD.FullCommand():
+		// Whatever the compiler prints would get mixed into the messages sent
+		// to the editor, so it is printed to stderr instead
+		out := os.Stdout
+		os.Stdout = os.Stderr
+		os.Exit(lsp.Serve(os.Stdin, out, targetTripple))
 	}
 	duration := time.Since(startTime)
 	log.Verbose("Total time taken: %s\n", duration)
@@ -129,6 +152,12 @@ func (c *Context) Build(buildDir string) {
 
 	program := ast.NewProgram()
 
+	// Types are laid out for the target before anything is compiled. Hosts
+	// we don't know the layout of keep the default one
+	if err := program.SetTarget(c.TargetTripple); err != nil {
+		log.Verbose("%s, using the data layout of %s\n", err, gtypes.DefaultTriple)
+	}
+
 	if !*arg.DisableRuntime {
 		program.ParseDep("", "runtime")
 	}
@@ -141,19 +170,16 @@ func (c *Context) Build(buildDir string) {
 	}
 
 	program.ParsePath(c.Input)
-	program.TargetTripple = c.TargetTripple
 
 	_, err := program.Congeal()
 	if err != nil {
-		log.Fatal("%s\n", err)
+		reportErrors(err)
 	}
 
 	options := ast.FunctionCompilationOptions{}
 	main, err := program.GetFunction("main", options)
 	if err != nil {
-		fmt.Println(color.Red("Failed to Compile"))
-		fmt.Println(err)
-		os.Exit(1)
+		reportErrors(err)
 	}
 	if main == nil {
 		log.Fatal("No function `main` found in compilation.\n")
@@ -178,6 +204,7 @@ func (c *Context) Build(buildDir string) {
 	linker.SetBuildDir(buildDir)
 	linker.SetOutput(c.Output)
 	linker.SetOptimize(*arg.Optimize)
+	linker.SetTriple(c.TargetTripple)
 
 	for _, clink := range program.CLinkages {
 		linker.AddObject(clink)
@@ -188,11 +215,32 @@ func (c *Context) Build(buildDir string) {
 	}
 
 	linker.AddObject(program.Emit(buildDir))
+
+	if *arg.StopAfterCompilation {
+		return
+	}
+
 	log.Timed("Linking", func() {
 		linker.Run()
 	})
 }
 
+// reportErrors prints the errors that stopped a build in the format asked for
+// with --error-format, and exits
+func reportErrors(err error) {
+	errs := diag.ListFrom(err)
+	if *arg.ErrorFormat == "json" {
+		errs.RenderJSON(os.Stdout)
+		os.Exit(1)
+	}
+
+	errs.Render(os.Stdout)
+	if errs.Errors() == 1 {
+		log.Fatal("could not compile due to the error above\n")
+	}
+	log.Fatal("could not compile due to %d errors\n", errs.Errors())
+}
+
 // Run a context with a given set of arguments
 func (c *Context) Run(args []string, buildDir string) {
 	cmd := exec.Command(c.Output, args...)
@@ -6,6 +6,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -29,11 +30,31 @@ import (
 
 // TestJob -
 type TestJob struct {
-	Name, sourcefile                         string
-	CompilerArgs, RunArgs                    []string
-	compilerError, RunStatus, CompilerStatus int
-	Input                                    string
-	compilerOutput, RunOutput                string
+	Name, sourcefile      string
+	CompilerArgs, RunArgs []string
+	RunStatus             int
+	Input                 string
+	RunOutput             string
+
+	// CompilerStatus is the exit status the compiler is expected to have.
+	// Programs that aren't expected to compile aren't run
+	CompilerStatus int
+
+	// CompilerOutput is text the compiler's output is expected to contain,
+	// like an error message. It isn't matched exactly, as the output has
+	// things like file paths that differ between machines
+	CompilerOutput string
+
+	// Session is a file of messages to send the language server, one per line,
+	// instead of building the program. $URI in them is replaced with the URI
+	// of the test's source file and $TEXT with it's text, and the responses are
+	// checked against RunOutput
+	Session string
+
+	// Command is a geode command, like fmt, to run on the test's source file
+	// instead of building it. What it prints to stdout is checked against
+	// RunOutput and it's exit status against CompilerStatus
+	Command string
 }
 
 type testResult struct {
@@ -115,8 +136,6 @@ func RunTests(testDirectory string) int {
 
 	results := make(chan testResult, len(jobs))
 
-	testRunCount := 0
-
 	util.RunCommand("geode", "clean")
 
 	go func() {
@@ -136,6 +155,28 @@ func RunTests(testDirectory string) int {
 			var err error
 			res := testResult{TestJob: job}
 
+			if job.Session != "" {
+				res.compilerError, res.RunOutput, res.compilerOutput, err = runSession(job)
+				if err != nil {
+					fmt.Printf("Error while running language server session:\n%s\n", err.Error())
+					os.Exit(1)
+				}
+				res.timetaken = time.Now().Sub(start)
+				results <- res
+				continue
+			}
+
+			if job.Command != "" {
+				res.compilerError, res.RunOutput, res.compilerOutput, err = runTool(job)
+				if err != nil {
+					fmt.Printf("Error while running geode %s:\n%s\n", job.Command, err.Error())
+					os.Exit(1)
+				}
+				res.timetaken = time.Now().Sub(start)
+				results <- res
+				continue
+			}
+
 			res.compilerError, err = runCommand(outBuf, "", "geode", buildArgs)
 			if err != nil {
 				fmt.Printf("Error while building test:\n%s\n", err.Error())
@@ -144,9 +185,17 @@ func RunTests(testDirectory string) int {
 			res.compilerOutput = outBuf.String()
 
 			if res.compilerError != 0 {
-				results <- res
 				res.RunStatus = -1
-				return
+				res.timetaken = time.Now().Sub(start)
+				results <- res
+				continue
+			}
+
+			// Tests built with --no-binary only check the compiler output
+			if _, err := os.Stat(outpath); os.IsNotExist(err) {
+				res.timetaken = time.Now().Sub(start)
+				results <- res
+				continue
 			}
 
 			// Run the test program
@@ -172,12 +221,8 @@ func RunTests(testDirectory string) int {
 
 			res.timetaken = elapsed
 			results <- res
-			testRunCount++
-
-			if testRunCount == len(jobs) {
-				close(results)
-			}
 		}
+		close(results)
 	}()
 
 	// Check results
@@ -194,16 +239,28 @@ func RunTests(testDirectory string) int {
 
 		// Check build errors
 
-		if (res.TestJob.compilerError == -1 && res.compilerError != res.TestJob.CompilerStatus) || (res.compilerError == res.TestJob.compilerError) {
+		if res.compilerError == res.TestJob.CompilerStatus {
 		} else {
 			fmt.Fprintf(errBuf, "CompilerStatus:\n")
-			fmt.Fprintf(errBuf, "Expected: %d\n", res.TestJob.compilerError)
-			fmt.Fprintf(errBuf, "Got:      %d\n", res.TestJob.CompilerStatus)
+			fmt.Fprintf(errBuf, "Expected: %d\n", res.TestJob.CompilerStatus)
+			fmt.Fprintf(errBuf, "Got:      %d\n", res.compilerError)
+			fmt.Fprintf(errBuf, "%s\n", res.compilerOutput)
 			failure = true
 		}
 
-		// Check run errors
-		if res.RunStatus == res.TestJob.RunStatus {
+		// The compiler output only has to contain the expected output, as it
+		// includes things like file paths that differ between machines
+		if strings.Contains(res.compilerOutput, res.TestJob.CompilerOutput) {
+		} else {
+			fmt.Fprintf(errBuf, "CompilerOutput:\n")
+			fmt.Fprintf(errBuf, "Expected: %q\n", res.TestJob.CompilerOutput)
+			fmt.Fprintf(errBuf, "Got:      %q\n", res.compilerOutput)
+			failure = true
+		}
+
+		// Programs that were expected not to compile have nothing to run
+		if res.TestJob.CompilerStatus != 0 && res.compilerError != 0 {
+		} else if res.RunStatus == res.TestJob.RunStatus {
 		} else {
 			fmt.Fprintf(errBuf, "RunStatus:\n")
 			fmt.Fprintf(errBuf, "Expected: %d\n", res.TestJob.RunStatus)
@@ -283,6 +340,95 @@ func runCommand(out io.Writer, input string, cmd string, args []string) (int, er
 	return 0, nil
 }
 
+// runSession sends the messages in a test's session to the language server, and
+// returns the exit status, the responses (one per line) and what it printed to
+// stderr
+func runSession(job TestJob) (int, string, string, error) {
+	abs, err := filepath.Abs(job.sourcefile)
+	if err != nil {
+		return -1, "", "", err
+	}
+	uri := "file://" + filepath.ToSlash(abs)
+
+	text, err := ioutil.ReadFile(job.sourcefile)
+	if err != nil {
+		return -1, "", "", err
+	}
+	quoted, _ := json.Marshal(string(text))
+
+	session, err := ioutil.ReadFile(filepath.Join(filepath.Dir(job.sourcefile), job.Session))
+	if err != nil {
+		return -1, "", "", err
+	}
+	input := &bytes.Buffer{}
+	for _, line := range strings.Split(string(session), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		line = strings.Replace(line, "$URI", uri, -1)
+		line = strings.Replace(line, "$TEXT", string(quoted), -1)
+		fmt.Fprintf(input, "Content-Length: %d\r\n\r\n%s", len(line), line)
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	command := exec.Command("geode", append([]string{"lsp"}, job.CompilerArgs...)...)
+	command.Stdin = input
+	command.Stdout, command.Stderr = stdout, stderr
+	command.Env = append(os.Environ(), "COLOR=0")
+
+	status := 0
+	if err := command.Run(); err != nil {
+		exiterr, ok := err.(*exec.ExitError)
+		if !ok {
+			return -1, "", "", err
+		}
+		status = exiterr.Sys().(syscall.WaitStatus).ExitStatus()
+	}
+
+	// The responses are shown one per line without their headers, and with
+	// the URI that differs between machines replaced
+	responses := &bytes.Buffer{}
+	out := stdout.String()
+	for {
+		start := strings.Index(out, "\r\n\r\n")
+		if start < 0 {
+			break
+		}
+		length := 0
+		fmt.Sscanf(strings.TrimPrefix(out[:start], "Content-Length: "), "%d", &length)
+		out = out[start+4:]
+		if length > len(out) {
+			length = len(out)
+		}
+		fmt.Fprintf(responses, "%s\n", strings.Replace(out[:length], uri, "$URI", -1))
+		out = out[length:]
+	}
+	return status, responses.String(), stderr.String(), nil
+}
+
+// runTool runs the command a test names on it's source file, and returns the
+// exit status, what it printed to stdout and what it printed to stderr
+func runTool(job TestJob) (int, string, string, error) {
+	args := []string{job.Command}
+	args = append(args, job.CompilerArgs...)
+	args = append(args, job.sourcefile)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	command := exec.Command("geode", args...)
+	command.Stdout, command.Stderr = stdout, stderr
+	command.Env = append(os.Environ(), "COLOR=0")
+
+	status := 0
+	if err := command.Run(); err != nil {
+		exiterr, ok := err.(*exec.ExitError)
+		if !ok {
+			return -1, "", "", err
+		}
+		status = exiterr.Sys().(syscall.WaitStatus).ExitStatus()
+	}
+	return status, stdout.String(), stderr.String(), nil
+}
+
 var testTemplate = `# {{NAME}}
 is main
 
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/geode-lang/geode/pkg/arg"
+	"github.com/geode-lang/geode/pkg/ast"
+	"github.com/geode-lang/geode/pkg/diag"
+	"github.com/geode-lang/geode/pkg/gtypes"
+	"github.com/geode-lang/geode/pkg/util/log"
+)
+
+// vetChecks returns the checks to vet with, which are all of them apart from
+// the ones turned off with --disable, or just the ones given with --only
+func vetChecks() []ast.VetCheck {
+	known := make(map[string]bool)
+	names := make([]string, 0, len(ast.VetChecks))
+	for _, check := range ast.VetChecks {
+		known[string(check)] = true
+		names = append(names, string(check))
+	}
+	for _, name := range append(*arg.VetOnly, *arg.VetDisable...) {
+		if !known[name] {
+			log.Fatal("unknown check %q, the checks are %s\n", name, strings.Join(names, ", "))
+		}
+	}
+
+	only := make(map[string]bool)
+	for _, name := range *arg.VetOnly {
+		only[name] = true
+	}
+	disabled := make(map[string]bool)
+	for _, name := range *arg.VetDisable {
+		disabled[name] = true
+	}
+
+	checks := make([]ast.VetCheck, 0, len(ast.VetChecks))
+	for _, check := range ast.VetChecks {
+		if (len(only) == 0 || only[string(check)]) && !disabled[string(check)] {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// VetPackage compiles the package at a path with the checks of geode vet, and
+// prints what they find in it's files. Nothing is linked or written out
+func VetPackage(input string, triple string) {
+	checks := vetChecks()
+
+	if _, err := os.Stat(input); os.IsNotExist(err) {
+		fmt.Printf("The file %q could not be found.\n", input)
+		os.Exit(-1)
+	}
+
+	program := ast.NewProgram()
+	if err := program.SetTarget(triple); err != nil {
+		log.Verbose("%s, using the data layout of %s\n", err, gtypes.DefaultTriple)
+	}
+	program.Vet(checks...)
+
+	if !*arg.DisableRuntime {
+		program.ParseDep("", "runtime")
+	}
+	program.Entry = input
+	program.ParsePath(input)
+
+	if _, err := program.Congeal(); err != nil {
+		reportErrors(err)
+	}
+
+	// Only the files of the package are vetted, not the ones it includes
+	dir, err := filepath.Abs(ast.ReduceToDir(input))
+	if err != nil {
+		log.Fatal("%s\n", err)
+	}
+	files := make([]string, 0)
+	for path := range program.Packages {
+		if filepath.Dir(path) == dir {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+
+	errs := diag.List{}
+	for _, file := range files {
+		if err := program.Check(file); err != nil {
+			errs.Add(err)
+		}
+	}
+	if len(errs) > 0 {
+		reportErrors(errs)
+	}
+
+	found := diag.List{}
+	for _, file := range files {
+		found = append(found, program.Warnings(file)...)
+	}
+	if len(found) == 0 {
+		os.Exit(0)
+	}
+
+	if *arg.ErrorFormat == "json" {
+		found.RenderJSON(os.Stdout)
+	} else {
+		found.Render(os.Stdout)
+	}
+	os.Exit(1)
+}
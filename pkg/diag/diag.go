@@ -0,0 +1,206 @@
+package diag
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity is how serious a diagnostic is
+type Severity int
+
+// The severities of diagnostics
+const (
+	Error Severity = iota
+	Warning
+	Note
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Warning:
+		return "warning"
+	case Note:
+		return "note"
+	}
+	return "error"
+}
+
+// MarshalJSON encodes the severity by it's name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Span is a range of columns on a line of a source file. Columns start at 1,
+// and the end column is just past the last one in the span
+type Span struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndColumn int    `json:"end_column"`
+	Label     string `json:"label,omitempty"`
+
+	// The text of the line, so the span can be shown
+	Source string `json:"-"`
+}
+
+func (s Span) String() string {
+	return fmt.Sprintf("%s:%d:%d", s.File, s.Line, s.Column)
+}
+
+// Suggestion is a change to the source that might fix a diagnostic
+type Suggestion struct {
+	Message     string `json:"message"`
+	Span        Span   `json:"span"`
+	Replacement string `json:"replacement"`
+}
+
+// Diagnostic is an error or warning about a program, at the place in the source
+// it is about. Diagnostics that aren't about any one place have no span
+type Diagnostic struct {
+	Severity    Severity     `json:"severity"`
+	Message     string       `json:"message"`
+	Span        *Span        `json:"span,omitempty"`
+	Labels      []Span       `json:"labels,omitempty"`
+	Notes       []string     `json:"notes,omitempty"`
+	Suggestions []Suggestion `json:"suggestions,omitempty"`
+}
+
+// Errorf returns an error diagnostic at a span. Spans without a line, like the
+// ones of nodes the compiler made itself, are left off
+func Errorf(span Span, format string, args ...interface{}) *Diagnostic {
+	d := &Diagnostic{
+		Severity: Error,
+		Message:  strings.TrimSpace(fmt.Sprintf(format, args...)),
+	}
+	if span.Line > 0 {
+		d.Span = &span
+	}
+	return d
+}
+
+// Warningf returns a warning diagnostic at a span
+func Warningf(span Span, format string, args ...interface{}) *Diagnostic {
+	d := Errorf(span, format, args...)
+	d.Severity = Warning
+	return d
+}
+
+// From returns an error as a diagnostic. Errors that aren't diagnostics become
+// ones without a span
+func From(err error) *Diagnostic {
+	if d, isDiag := err.(*Diagnostic); isDiag {
+		return d
+	}
+	return &Diagnostic{Severity: Error, Message: strings.TrimSpace(err.Error())}
+}
+
+// At gives an error a span if it doesn't have one already. Errors from deeper
+// in the compiler are usually more precise, so their spans are kept
+func At(span Span, err error) error {
+	if err == nil {
+		return nil
+	}
+	d := From(err)
+	if d.Span != nil || span.Line == 0 {
+		return d
+	}
+	d.Span = &span
+	return d
+}
+
+// Label adds a secondary span to the diagnostic, with a label explaining it
+func (d *Diagnostic) Label(span Span, format string, args ...interface{}) *Diagnostic {
+	span.Label = fmt.Sprintf(format, args...)
+	d.Labels = append(d.Labels, span)
+	return d
+}
+
+// Note adds a note to the end of the diagnostic
+func (d *Diagnostic) Note(format string, args ...interface{}) *Diagnostic {
+	d.Notes = append(d.Notes, fmt.Sprintf(format, args...))
+	return d
+}
+
+// Suggest adds a replacement of the text at a span that might fix the diagnostic
+func (d *Diagnostic) Suggest(span Span, replacement string, format string, args ...interface{}) *Diagnostic {
+	d.Suggestions = append(d.Suggestions, Suggestion{
+		Message:     fmt.Sprintf(format, args...),
+		Span:        span,
+		Replacement: replacement,
+	})
+	return d
+}
+
+func (d *Diagnostic) Error() string {
+	if d.Span == nil {
+		return d.Message
+	}
+	return fmt.Sprintf("%s: %s", d.Span, d.Message)
+}
+
+// List is a list of diagnostics, so they can all be reported at once instead of
+// just the first one
+type List []*Diagnostic
+
+// Add appends an error to the list as a diagnostic
+func (l *List) Add(err error) {
+	if list, isList := err.(List); isList {
+		*l = append(*l, list...)
+		return
+	}
+	*l = append(*l, From(err))
+}
+
+// Err returns the list as an error, or nil if it is empty
+func (l List) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// Errors returns the number of diagnostics in the list that are errors
+func (l List) Errors() int {
+	count := 0
+	for _, d := range l {
+		if d.Severity == Error {
+			count++
+		}
+	}
+	return count
+}
+
+// Sort orders the diagnostics by the file, line and column they are at. The
+// ones without a span are kept in order after the rest
+func (l List) Sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		a, b := l[i].Span, l[j].Span
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+func (l List) Error() string {
+	lines := make([]string, 0, len(l))
+	for _, d := range l {
+		lines = append(lines, d.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ListFrom returns an error as a list of diagnostics
+func ListFrom(err error) List {
+	l := List{}
+	l.Add(err)
+	return l
+}
@@ -0,0 +1,146 @@
+package diag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/geode-lang/geode/pkg/util/color"
+)
+
+// The width tabs are shown with
+const tabWidth = 4
+
+// Render writes the diagnostic for a terminal, with the source lines it is about
+// and carets under the spans in them:
+//
+//	error: unknown escape '\q'
+//	 --> main.g:6:6
+//	   |
+//	 6 | a = "\q"
+//	   |     ^^^^
+//	   = note: ...
+func (d *Diagnostic) Render(w io.Writer) {
+	title := fmt.Sprintf("%s:", d.Severity)
+	switch d.Severity {
+	case Error:
+		title = color.Red(title)
+	case Warning:
+		title = color.Yellow(title)
+	default:
+		title = color.Cyan(title)
+	}
+	fmt.Fprintf(w, "%s %s\n", title, color.Bold(d.Message))
+
+	spans := make([]Span, 0, 1+len(d.Labels))
+	if d.Span != nil {
+		spans = append(spans, *d.Span)
+	}
+	spans = append(spans, d.Labels...)
+
+	// The gutter is as wide as the largest line number
+	width := 1
+	for _, s := range spans {
+		width = max(width, len(fmt.Sprint(s.Line)))
+	}
+	for _, s := range d.Suggestions {
+		width = max(width, len(fmt.Sprint(s.Span.Line)))
+	}
+	gutter := strings.Repeat(" ", width)
+
+	if d.Span != nil {
+		fmt.Fprintf(w, "%s%s %s\n", gutter, color.Blue("-->"), d.Span)
+	}
+	for i, s := range spans {
+		// Spans in other files say where they are
+		if i > 0 && d.Span != nil && s.File != d.Span.File {
+			fmt.Fprintf(w, "%s%s %s\n", gutter, color.Blue(":::"), s)
+		}
+		if i == 0 && d.Span != nil {
+			renderSpan(w, s, "^", color.Red, width)
+		} else {
+			renderSpan(w, s, "-", color.Blue, width)
+		}
+	}
+
+	for _, note := range d.Notes {
+		fmt.Fprintf(w, "%s %s note: %s\n", gutter, color.Blue("="), note)
+	}
+	for _, s := range d.Suggestions {
+		fmt.Fprintf(w, "%s %s help: %s\n", gutter, color.Blue("="), s.Message)
+		renderSuggestion(w, s, width)
+	}
+}
+
+// renderSuggestion writes the line a suggestion changes as it would be once it
+// is changed, with the replacement underlined
+func renderSuggestion(w io.Writer, s Suggestion, width int) {
+	span := s.Span
+	if span.Source == "" || span.Column < 1 || span.EndColumn < span.Column || span.EndColumn-1 > len(span.Source) {
+		return
+	}
+	span.Source = span.Source[:span.Column-1] + s.Replacement + span.Source[span.EndColumn-1:]
+	span.EndColumn = span.Column + len(s.Replacement)
+	renderSpan(w, span, "~", color.Green, width)
+}
+
+// renderSpan writes the line a span is on, and underlines the span with a marker
+func renderSpan(w io.Writer, s Span, marker string, paint func(string) string, width int) {
+	bar := color.Blue(fmt.Sprintf("%*s |", width, ""))
+	fmt.Fprintf(w, "%s\n", bar)
+	if s.Source == "" {
+		return
+	}
+
+	line := expandTabs(s.Source)
+	fmt.Fprintf(w, "%s %s\n", color.Blue(fmt.Sprintf("%*d |", width, s.Line)), strings.TrimRight(line, " \r"))
+
+	start := visualColumn(s.Source, s.Column)
+	end := visualColumn(s.Source, s.EndColumn)
+	carets := strings.Repeat(marker, max(end-start, 1))
+	if s.Label != "" {
+		carets += " " + s.Label
+	}
+	fmt.Fprintf(w, "%s %s%s\n", bar, strings.Repeat(" ", start-1), paint(carets))
+}
+
+// expandTabs replaces the tabs in a line with spaces
+func expandTabs(line string) string {
+	return strings.Replace(line, "\t", strings.Repeat(" ", tabWidth), -1)
+}
+
+// visualColumn returns where a column of a line is shown once the tabs before it
+// are expanded
+func visualColumn(line string, column int) int {
+	if column-1 > len(line) {
+		return column + strings.Count(line, "\t")*(tabWidth-1)
+	}
+	return column + strings.Count(line[:column-1], "\t")*(tabWidth-1)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// RenderJSON writes the diagnostics as JSON, one per line, for editors and CI
+func (l List) RenderJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, d := range l {
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render writes the diagnostics for a terminal
+func (l List) Render(w io.Writer) {
+	for _, d := range l {
+		d.Render(w)
+		fmt.Fprintln(w)
+	}
+}
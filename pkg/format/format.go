@@ -0,0 +1,273 @@
+// Package format formats Geode source code in the one style every program is
+// written in: indented with tabs, one space around binary operators and none
+// inside brackets, and opening braces on the line of what they belong to.
+//
+// Programs are formatted from their tokens, and only the whitespace between them
+// changes. Comments stay on the lines they are on, next to the code they are
+// about, and the line breaks in the source are kept apart from runs of blank
+// lines, which become one
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/geode-lang/geode/pkg/ast"
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
+// Source returns a file formatted. Files with syntax errors aren't formatted,
+// and the errors are returned instead
+func Source(src *lexer.Sourcefile) (string, error) {
+	tokens := lexer.Lex(src)
+	if _, err := ast.Parse(tokens); err != nil {
+		return "", err
+	}
+
+	formatted := Tokens(tokens)
+
+	// Formatting only ever changes whitespace, which is checked so it can't
+	// change what a program means
+	out, _ := lexer.NewSourcefile(src.Path)
+	out.LoadString(formatted)
+	if !sameTokens(tokens, lexer.Lex(out)) {
+		return "", fmt.Errorf("formatting %s would change the tokens in it, so it was left as it is", src.Path)
+	}
+	return formatted, nil
+}
+
+// sameTokens returns if two lists of tokens are the same apart from the space
+// between them
+func sameTokens(a, b []lexer.Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || strings.TrimSpace(a[i].Text()) != strings.TrimSpace(b[i].Text()) {
+			return false
+		}
+	}
+	return true
+}
+
+// line is the tokens on a line of the source
+type line struct {
+	tokens []lexer.Token
+	blank  bool // there is a blank line before it
+}
+
+// Tokens writes the tokens of a file out formatted
+func Tokens(tokens []lexer.Token) string {
+	lines := splitLines(tokens)
+
+	buff := &bytes.Buffer{}
+	indents := indentLevels(lines)
+	for i, l := range lines {
+		if i > 0 && l.blank && !opens(last(lines[i-1])) && !closes(l.tokens[0]) {
+			fmt.Fprintln(buff)
+		}
+		fmt.Fprintf(buff, "%s", strings.Repeat("\t", indents[i]))
+		for j, tok := range l.tokens {
+			if j > 0 && spaced(l.tokens, j) {
+				fmt.Fprintf(buff, " ")
+			}
+			fmt.Fprintf(buff, "%s", strings.TrimRight(tok.Text(), " \t\r"))
+		}
+		fmt.Fprintln(buff)
+	}
+	return buff.String()
+}
+
+// splitLines groups the tokens into the lines they are on. Opening braces on a
+// line of their own are moved up to the line before them, and so is an else
+// after the brace that closes an if
+func splitLines(tokens []lexer.Token) []line {
+	lines := make([]line, 0)
+	for i, tok := range tokens {
+		breaks := strings.Count(tok.Leading, "\n")
+		if i > 0 && breaks == 0 {
+			lines[len(lines)-1].tokens = append(lines[len(lines)-1].tokens, tok)
+			continue
+		}
+
+		if len(lines) > 0 {
+			prev := last(lines[len(lines)-1])
+			if (tok.Value == "{" && !prev.Is(lexer.TokComment)) || (tok.Is(lexer.TokElse) && prev.Value == "}") {
+				lines[len(lines)-1].tokens = append(lines[len(lines)-1].tokens, tok)
+				continue
+			}
+		}
+		lines = append(lines, line{[]lexer.Token{tok}, breaks > 1})
+	}
+	return lines
+}
+
+// indentLevels returns how far each line is indented. Lines are indented once
+// more than the line that opens the brackets they are in, however many brackets
+// it opens, so a call that takes a closure has it's body indented once
+func indentLevels(lines []line) []int {
+	type bracket struct {
+		indents bool // the line it was opened on indents the lines after it
+	}
+	open := make([]bracket, 0)
+	level := 0
+	levels := make([]int, len(lines))
+
+	for i, l := range lines {
+		// Lines that start by closing brackets are indented like the line that
+		// opened them
+		j := 0
+		for ; j < len(l.tokens) && closes(l.tokens[j]) && len(open) > 0; j++ {
+			if open[len(open)-1].indents {
+				level--
+			}
+			open = open[:len(open)-1]
+		}
+		levels[i] = level
+
+		opened := len(open)
+		for _, tok := range l.tokens[j:] {
+			if opens(tok) {
+				open = append(open, bracket{})
+			} else if closes(tok) && len(open) > 0 {
+				if open[len(open)-1].indents {
+					level--
+				}
+				open = open[:len(open)-1]
+				if len(open) < opened {
+					opened = len(open)
+				}
+			}
+		}
+		if len(open) > opened {
+			open[len(open)-1].indents = true
+			level++
+		}
+	}
+	return levels
+}
+
+func last(l line) lexer.Token {
+	return l.tokens[len(l.tokens)-1]
+}
+
+func opens(tok lexer.Token) bool {
+	return tok.Value == "{" || tok.Value == "(" || tok.Value == "["
+}
+
+func closes(tok lexer.Token) bool {
+	return tok.Value == "}" || tok.Value == ")" || tok.Value == "]"
+}
+
+// spaced returns if there is a space between a token on a line and the one
+// before it. Places the style doesn't decide keep whether they had a space
+func spaced(tokens []lexer.Token, i int) bool {
+	return wantsSpace(tokens, i) || !joins(tokens[i-1], tokens[i])
+}
+
+// wantsSpace returns if the style puts a space before a token on a line
+func wantsSpace(tokens []lexer.Token, i int) bool {
+	a, b := tokens[i-1], tokens[i]
+	written := b.Leading != ""
+
+	switch {
+	case b.Is(lexer.TokComment):
+		return true
+	case b.Value == "," || b.Value == ";" || b.Value == ")" || b.Value == "]":
+		return false
+	case a.Value == "(" || a.Value == "[":
+		return false
+	case a.Value == "." || b.Value == ".":
+		return false
+	case a.Value == "," || a.Value == ";":
+		return true
+	case a.Value == "{" && b.Value == "}":
+		return written
+	case a.Value == "{" || b.Value == "{" || a.Value == "}" || b.Value == "}":
+		return true
+	case isAngle(tokens, i) || (isAngle(tokens, i-1) && a.Value == "<"):
+		// Type arguments, like Box<int>, are written without spaces
+		return false
+	case isAngle(tokens, i-1):
+		// The type ends at the closing bracket, like in `Box<int>* b`, unless it
+		// is called or indexed
+		return written && b.Value != "(" && b.Value != "["
+	case b.Value == "(" && a.Value == ")":
+		// Functions that return more than one value, like `func f() (int, int)`
+		return written
+	case isUnary(tokens, i-1):
+		return false
+	case b.Value == "(" || b.Value == "[":
+		// Calls, subscripts and types like `int[]` and `func(int)` are
+		// written against what they are after
+		if endsValue(a) || a.Is(lexer.TokFuncDefn) {
+			return false
+		}
+		return written || isOperator(a) || isKeyword(a)
+	case b.Value == "*" && (a.Is(lexer.TokType) || a.Value == "*") && !written:
+		// Pointer types, like int*
+		return false
+	case isOperator(a) || isOperator(b):
+		return true
+	}
+	return written
+}
+
+// joins returns if two tokens are lexed the same when they are written without
+// a space between them
+func joins(a, b lexer.Token) bool {
+	tokens := lexer.QuickLex(a.Text() + b.Text())
+	return len(tokens) == 2 && tokens[0].Value == a.Value && tokens[1].Value == b.Value
+}
+
+// endsValue returns if an expression can end with a token
+func endsValue(tok lexer.Token) bool {
+	return tok.Is(lexer.TokIdent, lexer.TokType, lexer.TokNumber, lexer.TokString, lexer.TokChar, lexer.TokBool, lexer.TokNil) ||
+		tok.Value == ")" || tok.Value == "]"
+}
+
+func isOperator(tok lexer.Token) bool {
+	return tok.Is(lexer.TokOper, lexer.TokRightArrow, lexer.TokLeftArrow) || lexer.TokenIsOperator(tok.Type)
+}
+
+// isAngle returns if a token on a line opens or closes the type arguments of a
+// type, like the brackets in Box<int>. Type arguments always come right after
+// the name of a type, which tells them apart from comparisons. The lexer keeps
+// operators together, so the end of `Node<T>*` is one token
+func isAngle(tokens []lexer.Token, i int) bool {
+	depth := 0
+	for j, tok := range tokens[:i+1] {
+		angle := false
+		if tok.Value == "<" && j > 0 && tokens[j-1].Is(lexer.TokType) {
+			depth++
+			angle = true
+		} else if isOperator(tok) && depth > 0 && strings.HasPrefix(tok.Value, ">") {
+			depth -= len(tok.Value) - len(strings.TrimLeft(tok.Value, ">"))
+			if depth < 0 {
+				depth = 0
+			}
+			angle = true
+		}
+		if j == i {
+			return angle
+		}
+	}
+	return false
+}
+
+func isKeyword(tok lexer.Token) bool {
+	return tok.Is(lexer.TokIf, lexer.TokFor, lexer.TokWhile, lexer.TokReturn, lexer.TokMatch, lexer.TokIn, lexer.TokDefer, lexer.TokElse)
+}
+
+// isUnary returns if an operator is applied to the value after it, like the
+// minus in `a = -b`
+func isUnary(tokens []lexer.Token, i int) bool {
+	switch tokens[i].Value {
+	case "!":
+		return true
+	case "-", "*", "&":
+		return isOperator(tokens[i]) && (i == 0 || !endsValue(tokens[i-1]))
+	}
+	return false
+}
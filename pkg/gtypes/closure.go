@@ -0,0 +1,47 @@
+package gtypes
+
+import (
+	"github.com/llir/llvm/ir/types"
+)
+
+// ClosureType type is a Geode closure type.
+type ClosureType struct {
+	// The signature of the closure as seen by the caller, without the
+	// environment pointer.
+	Sig *types.FuncType
+
+	// A Geode closure value is implemented as an LLVM struct type.
+	//    { ret (i8*, params...)*, i8* }
+	*types.StructType
+}
+
+// NewClosure returns a new Geode closure type based on the given return and
+// parameter types.
+func NewClosure(ret types.Type, params ...types.Type) *ClosureType {
+	env := types.NewPointer(types.I8)
+	fn := types.NewFunc(ret, append([]types.Type{env}, params...)...)
+	typ := types.NewStruct(types.NewPointer(fn), env)
+	return &ClosureType{
+		Sig:        types.NewFunc(ret, params...),
+		StructType: typ,
+	}
+}
+
+// Underlying returns the underlying LLVM IR type of the Geode closure type.
+func (t *ClosureType) Underlying() types.Type {
+	return t.StructType
+}
+
+// Equal reports whether t and u are of equal type.
+func (t *ClosureType) Equal(u types.Type) bool {
+	if u, ok := u.(*ClosureType); ok {
+		return t.StructType.Equal(u.StructType)
+	}
+	return t.StructType.Equal(u)
+}
+
+// FuncType returns the type of the function the closure calls, which takes
+// the environment as it's first argument.
+func (t *ClosureType) FuncType() *types.FuncType {
+	return t.Fields[0].(*types.PointerType).ElemType.(*types.FuncType)
+}
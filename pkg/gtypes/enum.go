@@ -0,0 +1,74 @@
+package gtypes
+
+import (
+	"github.com/llir/llvm/ir/types"
+)
+
+// EnumVariant is a single variant of a Geode enum, with the names and types
+// of the fields it carries.
+type EnumVariant struct {
+	Name   string
+	Fields []string
+
+	// The fields of the variant, as they are laid out in the payload.
+	Payload *types.StructType
+}
+
+// EnumType type is a Geode enum type. Each value holds the tag of it's variant
+// and the fields of that variant, if it has any.
+type EnumType struct {
+	// Variants, in the order of their tags.
+	Variants []*EnumVariant
+
+	// A Geode enum value is implemented as an LLVM struct type.
+	//    { i32, [n x i64] }
+	// The payload is large enough to hold the fields of any variant. It is made
+	// of i64s so the fields are aligned.
+	*types.StructType
+}
+
+// NewEnum returns a new Geode enum type. The variants may be specified through
+// t.SetVariants once the types of their fields are known.
+func NewEnum() *EnumType {
+	return &EnumType{
+		StructType: types.NewStruct(types.I32, types.NewArray(0, types.I64)),
+	}
+}
+
+// SetVariants sets the variants of the enum, and sizes the payload to fit the
+// largest of them.
+func (t *EnumType) SetVariants(variants ...*EnumVariant) {
+	words := 0
+	for _, v := range variants {
+		n := (ByteCount(v.Payload) + 7) / 8
+		if n > words {
+			words = n
+		}
+	}
+	t.Variants = variants
+	t.StructType.Fields[1] = types.NewArray(uint64(words), types.I64)
+}
+
+// Underlying returns the underlying LLVM IR type of the Geode enum type.
+func (t *EnumType) Underlying() types.Type {
+	return t.StructType
+}
+
+// Equal reports whether t and u are of equal type.
+func (t *EnumType) Equal(u types.Type) bool {
+	if u, ok := u.(*EnumType); ok {
+		return t.StructType.Equal(u.StructType)
+	}
+	return t.StructType.Equal(u)
+}
+
+// VariantIndex returns the tag of the variant with the given name, or -1 if not
+// present.
+func (t *EnumType) VariantIndex(name string) int {
+	for i, v := range t.Variants {
+		if v.Name == name {
+			return i
+		}
+	}
+	return -1
+}
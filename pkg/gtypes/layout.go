@@ -0,0 +1,288 @@
+package gtypes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+// Target is the data layout of the target being compiled for. The sizes and
+// alignments of types are computed from it. It is changed with SetTarget.
+var Target = mustParseDataLayout(KnownTargets[DefaultTriple])
+
+// DataLayout describes how values are laid out in memory on some target. It is
+// parsed from an LLVM data layout string, and answers questions about the size
+// and alignment of types the same way LLVM does. All sizes are in bytes.
+type DataLayout struct {
+	BigEndian bool
+
+	PointerSize  int
+	PointerAlign int
+
+	// The ABI alignment of integer, float and vector types, by their bit size.
+	IntAlign    map[int]int
+	FloatAlign  map[int]int
+	VectorAlign map[int]int
+
+	// The minimum alignment of structs.
+	AggregateAlign int
+
+	// The natural alignment of the stack, or 0 if unspecified.
+	StackAlign int
+
+	source string
+}
+
+// ParseDataLayout parses an LLVM data layout string, like "e-m:e-i64:64-n8:16:32:64-S128".
+// Anything the string doesn't specify takes LLVM's default.
+// See https://llvm.org/docs/LangRef.html#data-layout
+func ParseDataLayout(s string) (*DataLayout, error) {
+	l := &DataLayout{
+		PointerSize:    8,
+		PointerAlign:   8,
+		IntAlign:       map[int]int{1: 1, 8: 1, 16: 2, 32: 4, 64: 4},
+		FloatAlign:     map[int]int{16: 2, 32: 4, 64: 8, 128: 16},
+		VectorAlign:    map[int]int{64: 8, 128: 16},
+		AggregateAlign: 1,
+		source:         s,
+	}
+	if s == "" {
+		return l, nil
+	}
+
+	for _, spec := range strings.Split(s, "-") {
+		if spec == "" {
+			return nil, fmt.Errorf("invalid data layout %q: empty specification", s)
+		}
+
+		kind := spec[:1]
+		parts := strings.Split(spec[1:], ":")
+
+		switch kind {
+		case "e", "E":
+			l.BigEndian = kind == "E"
+		case "m", "n", "F", "P", "A", "G":
+			// Mangling, native integer widths, function pointer alignment and
+			// address spaces don't change the layout of values.
+		case "S":
+			bits, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid data layout %q: bad stack alignment %q", s, spec)
+			}
+			l.StackAlign = bits / 8
+		case "p":
+			// Only the default address space is used
+			if parts[0] != "" && parts[0] != "0" {
+				continue
+			}
+			if len(parts) < 3 {
+				return nil, fmt.Errorf("invalid data layout %q: bad pointer specification %q", s, spec)
+			}
+			size, err1 := strconv.Atoi(parts[1])
+			align, err2 := strconv.Atoi(parts[2])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid data layout %q: bad pointer specification %q", s, spec)
+			}
+			l.PointerSize = size / 8
+			l.PointerAlign = align / 8
+		case "i", "f", "v", "a":
+			bits, align, err := parseAlignSpec(kind, parts)
+			if err != nil {
+				return nil, fmt.Errorf("invalid data layout %q: %s", s, err)
+			}
+			switch kind {
+			case "i":
+				l.IntAlign[bits] = align
+			case "f":
+				l.FloatAlign[bits] = align
+			case "v":
+				l.VectorAlign[bits] = align
+			case "a":
+				if align == 0 {
+					align = 1
+				}
+				l.AggregateAlign = align
+			}
+		default:
+			return nil, fmt.Errorf("invalid data layout %q: unknown specification %q", s, spec)
+		}
+	}
+	return l, nil
+}
+
+// parseAlignSpec parses the size and ABI alignment of a specification like `i64:64` or `a:0:64`
+func parseAlignSpec(kind string, parts []string) (int, int, error) {
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("bad alignment specification %q", kind+strings.Join(parts, ":"))
+	}
+	bits := 0
+	if kind != "a" {
+		n, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return 0, 0, fmt.Errorf("bad size in %q", kind+strings.Join(parts, ":"))
+		}
+		bits = n
+	}
+	align, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("bad alignment in %q", kind+strings.Join(parts, ":"))
+	}
+	return bits, align / 8, nil
+}
+
+func mustParseDataLayout(s string) *DataLayout {
+	l, err := ParseDataLayout(s)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// String returns the data layout string the layout was parsed from.
+func (l *DataLayout) String() string {
+	return l.source
+}
+
+// SizeOf returns the number of bytes between successive values of the type in
+// memory, including any padding needed for their alignment. This is what C's
+// sizeof would return.
+func (l *DataLayout) SizeOf(t types.Type) int {
+	return alignTo(l.StoreSizeOf(t), l.AlignOf(t))
+}
+
+// StoreSizeOf returns the number of bytes that may be written when storing a value
+// of the type, without the padding after it.
+func (l *DataLayout) StoreSizeOf(t types.Type) int {
+	if g, ok := t.(Type); ok {
+		t = g.Underlying()
+	}
+
+	switch t := t.(type) {
+	case *types.IntType:
+		return (int(t.BitSize) + 7) / 8
+	case *types.FloatType:
+		if t.Kind == types.FloatKindX86_FP80 {
+			return 10
+		}
+		return FloatBitSize(t) / 8
+	case *types.PointerType:
+		return l.PointerSize
+	case *types.ArrayType:
+		return int(t.Len) * l.SizeOf(t.ElemType)
+	case *types.VectorType:
+		return (int(t.Len)*l.bitSize(t.ElemType) + 7) / 8
+	case *types.StructType:
+		if t.Opaque || len(t.Fields) == 0 {
+			return 0
+		}
+		last := len(t.Fields) - 1
+		return alignTo(l.OffsetOf(t, last)+l.SizeOf(t.Fields[last]), l.AlignOf(t))
+	}
+
+	// Void, functions, labels and metadata have no size
+	return 0
+}
+
+// AlignOf returns the ABI alignment of the type in bytes.
+func (l *DataLayout) AlignOf(t types.Type) int {
+	if g, ok := t.(Type); ok {
+		t = g.Underlying()
+	}
+
+	switch t := t.(type) {
+	case *types.IntType:
+		return l.intAlign(int(t.BitSize))
+	case *types.FloatType:
+		if align, ok := l.FloatAlign[FloatBitSize(t)]; ok {
+			return align
+		}
+		return nextPowerOfTwo(l.StoreSizeOf(t))
+	case *types.PointerType:
+		return l.PointerAlign
+	case *types.ArrayType:
+		return l.AlignOf(t.ElemType)
+	case *types.VectorType:
+		if align, ok := l.VectorAlign[int(t.Len)*l.bitSize(t.ElemType)]; ok {
+			return align
+		}
+		return nextPowerOfTwo(l.StoreSizeOf(t))
+	case *types.StructType:
+		if t.Packed {
+			return 1
+		}
+		align := l.AggregateAlign
+		for _, field := range t.Fields {
+			if a := l.AlignOf(field); a > align {
+				align = a
+			}
+		}
+		return align
+	}
+	return 1
+}
+
+// OffsetOf returns the offset in bytes of a field in a struct.
+func (l *DataLayout) OffsetOf(t *types.StructType, field int) int {
+	offset := 0
+	for i, f := range t.Fields {
+		if !t.Packed {
+			offset = alignTo(offset, l.AlignOf(f))
+		}
+		if i == field {
+			break
+		}
+		offset += l.SizeOf(f)
+	}
+	return offset
+}
+
+// intAlign returns the alignment of an integer with the given bit size. Sizes
+// without their own alignment use the alignment of the next larger integer
+// that has one, or the largest one if there isn't a larger one.
+func (l *DataLayout) intAlign(bits int) int {
+	if align, ok := l.IntAlign[bits]; ok {
+		return align
+	}
+	best, largest := -1, -1
+	for size := range l.IntAlign {
+		if size > bits && (best == -1 || size < best) {
+			best = size
+		}
+		if size > largest {
+			largest = size
+		}
+	}
+	if best == -1 {
+		best = largest
+	}
+	return l.IntAlign[best]
+}
+
+// bitSize returns the size of a primitive type in bits
+func (l *DataLayout) bitSize(t types.Type) int {
+	switch t := t.(type) {
+	case *types.IntType:
+		return int(t.BitSize)
+	case *types.FloatType:
+		return FloatBitSize(t)
+	}
+	return l.StoreSizeOf(t) * 8
+}
+
+// alignTo rounds n up to a multiple of align
+func alignTo(n, align int) int {
+	if align <= 1 {
+		return n
+	}
+	return (n + align - 1) / align * align
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
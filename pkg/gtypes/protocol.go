@@ -0,0 +1,60 @@
+package gtypes
+
+import (
+	"github.com/llir/llvm/ir/types"
+)
+
+// ProtocolType type is a Geode protocol type.
+type ProtocolType struct {
+	// Method names, in the order they appear in the vtable.
+	Methods []string
+
+	// VTable is the struct of function pointers that implements the protocol
+	// for some class. Each method takes the data pointer as it's first argument.
+	VTable *types.StructType
+
+	// A Geode protocol value is implemented as an LLVM struct type.
+	//    { i8*, vtable* }
+	*types.StructType
+}
+
+// NewProtocol returns a new Geode protocol type with the given method names.
+// The method signatures may be specified through t.VTable.Fields.
+func NewProtocol(methods ...string) *ProtocolType {
+	vtable := types.NewStruct()
+	typ := types.NewStruct(types.NewPointer(types.I8), types.NewPointer(vtable))
+	return &ProtocolType{
+		Methods:    methods,
+		VTable:     vtable,
+		StructType: typ,
+	}
+}
+
+// Underlying returns the underlying LLVM IR type of the Geode protocol type.
+func (t *ProtocolType) Underlying() types.Type {
+	return t.StructType
+}
+
+// Equal reports whether t and u are of equal type.
+func (t *ProtocolType) Equal(u types.Type) bool {
+	if u, ok := u.(*ProtocolType); ok {
+		return t.StructType.Equal(u.StructType)
+	}
+	return t.StructType.Equal(u)
+}
+
+// MethodIndex returns the index of some method in the vtable, or -1 if not
+// present.
+func (t *ProtocolType) MethodIndex(name string) int {
+	for i, n := range t.Methods {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// MethodType returns the function type of the method at the given vtable index.
+func (t *ProtocolType) MethodType(index int) *types.FuncType {
+	return t.VTable.Fields[index].(*types.PointerType).ElemType.(*types.FuncType)
+}
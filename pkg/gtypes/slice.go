@@ -10,14 +10,15 @@ type SliceType struct {
 	ElemType types.Type
 
 	// A Geode slice type is implemented as an LLVM struct type.
-	//    { elem*, length }
+	//    { elem*, length, capacity }
 	*types.StructType
 }
 
 // NewSlice returns a new Geode slice type based on the given element type.
 func NewSlice(elem types.Type) *SliceType {
 	length := types.I64
-	typ := types.NewStruct(types.NewPointer(elem), length)
+	capacity := types.I64
+	typ := types.NewStruct(types.NewPointer(elem), length, capacity)
 	return &SliceType{
 		ElemType:   elem,
 		StructType: typ,
@@ -28,3 +29,11 @@ func NewSlice(elem types.Type) *SliceType {
 func (t *SliceType) Underlying() types.Type {
 	return t.StructType
 }
+
+// Equal reports whether t and u are of equal type.
+func (t *SliceType) Equal(u types.Type) bool {
+	if u, ok := u.(*SliceType); ok {
+		return t.StructType.Equal(u.StructType)
+	}
+	return t.StructType.Equal(u)
+}
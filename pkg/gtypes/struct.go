@@ -36,3 +36,11 @@ func (t *StructType) FieldIndex(name string) int {
 	}
 	return -1
 }
+
+// Equal reports whether t and u are of equal type.
+func (t *StructType) Equal(u types.Type) bool {
+	if u, ok := u.(*StructType); ok {
+		return t.StructType.Equal(u.StructType)
+	}
+	return t.StructType.Equal(u)
+}
@@ -0,0 +1,71 @@
+package gtypes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultTriple is the target used when no other target is known
+const DefaultTriple = "x86_64-unknown-linux-gnu"
+
+// KnownTargets maps the triples Geode can compile for to their LLVM data layouts
+var KnownTargets = map[string]string{
+	"x86_64-unknown-linux-gnu":  "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128",
+	"x86_64-apple-darwin":       "e-m:o-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128",
+	"aarch64-unknown-linux-gnu": "e-m:e-i8:8:32-i16:16:32-i64:64-i128:128-n32:64-S128",
+	"aarch64-apple-darwin":      "e-m:o-i64:64-i128:128-n32:64-S128",
+}
+
+// KnownTriples returns the triples in KnownTargets, sorted
+func KnownTriples() []string {
+	triples := make([]string, 0, len(KnownTargets))
+	for triple := range KnownTargets {
+		triples = append(triples, triple)
+	}
+	sort.Strings(triples)
+	return triples
+}
+
+// LookupTarget returns the data layout of a target triple. Triples only have to
+// match a known target by their architecture and operating system, so the triple
+// clang reports for the host, like "x86_64-pc-linux-gnu" or "arm64-apple-macosx10.14.0",
+// is found as well.
+func LookupTarget(triple string) (*DataLayout, error) {
+	if layout, ok := KnownTargets[triple]; ok {
+		return ParseDataLayout(layout)
+	}
+
+	parts := strings.Split(triple, "-")
+	arch := parts[0]
+	if arch == "arm64" {
+		arch = "aarch64"
+	}
+
+	for _, part := range parts[1:] {
+		var known string
+		switch {
+		case strings.HasPrefix(part, "linux"):
+			known = arch + "-unknown-linux-gnu"
+		case strings.HasPrefix(part, "darwin"), strings.HasPrefix(part, "macos"):
+			known = arch + "-apple-darwin"
+		default:
+			continue
+		}
+		if layout, ok := KnownTargets[known]; ok {
+			return ParseDataLayout(layout)
+		}
+	}
+
+	return nil, fmt.Errorf("unknown target %q, the known targets are: %s", triple, strings.Join(KnownTriples(), ", "))
+}
+
+// SetTarget changes the target that types are laid out for
+func SetTarget(triple string) error {
+	layout, err := LookupTarget(triple)
+	if err != nil {
+		return err
+	}
+	Target = layout
+	return nil
+}
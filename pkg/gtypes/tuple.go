@@ -0,0 +1,37 @@
+package gtypes
+
+import (
+	"github.com/llir/llvm/ir/types"
+)
+
+// TupleType type is the type of the values returned by a Geode function
+// that returns more than one value.
+type TupleType struct {
+	// The types of the values, in order.
+	Elems []types.Type
+
+	// A Geode tuple is implemented as an anonymous LLVM struct type.
+	//    { elems... }
+	*types.StructType
+}
+
+// NewTuple returns a new Geode tuple type based on the given element types.
+func NewTuple(elems ...types.Type) *TupleType {
+	return &TupleType{
+		Elems:      elems,
+		StructType: types.NewStruct(elems...),
+	}
+}
+
+// Underlying returns the underlying LLVM IR type of the Geode tuple type.
+func (t *TupleType) Underlying() types.Type {
+	return t.StructType
+}
+
+// Equal reports whether t and u are of equal type.
+func (t *TupleType) Equal(u types.Type) bool {
+	if u, ok := u.(*TupleType); ok {
+		return t.StructType.Equal(u.StructType)
+	}
+	return t.StructType.Equal(u)
+}
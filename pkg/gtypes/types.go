@@ -21,6 +21,30 @@ func IsSlice(t types.Type) bool {
 	return ok
 }
 
+// IsProtocol reports whether the given type is a Geode protocol type.
+func IsProtocol(t types.Type) bool {
+	_, ok := t.(*ProtocolType)
+	return ok
+}
+
+// IsClosure reports whether the given type is a Geode closure type.
+func IsClosure(t types.Type) bool {
+	_, ok := t.(*ClosureType)
+	return ok
+}
+
+// IsEnum reports whether the given type is a Geode enum type.
+func IsEnum(t types.Type) bool {
+	_, ok := t.(*EnumType)
+	return ok
+}
+
+// IsTuple reports whether the given type is a Geode tuple type.
+func IsTuple(t types.Type) bool {
+	_, ok := t.(*TupleType)
+	return ok
+}
+
 // IsStruct reports whether the given type is a struct type.
 func IsStruct(t types.Type) bool {
 	if u, ok := t.(Type); ok {
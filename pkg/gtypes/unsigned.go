@@ -0,0 +1,26 @@
+package gtypes
+
+import (
+	"github.com/llir/llvm/ir/types"
+)
+
+// Unsigned integer types of Geode.
+//
+// LLVM IR integers have no sign, so the unsigned types are plain LLVM integer
+// types that are told apart from the signed ones by identity. They are equal to
+// the signed types of the same size through types.Equal.
+var (
+	U8  = &types.IntType{BitSize: 8}
+	U16 = &types.IntType{BitSize: 16}
+	U32 = &types.IntType{BitSize: 32}
+	U64 = &types.IntType{BitSize: 64}
+)
+
+// IsUnsigned reports whether the given type is an unsigned integer type.
+func IsUnsigned(t types.Type) bool {
+	switch t {
+	case U8, U16, U32, U64:
+		return true
+	}
+	return false
+}
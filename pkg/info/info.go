@@ -31,6 +31,12 @@ func AddNode(n Item) {
 	gic.nodes = append(gic.nodes, n)
 }
 
+// Reset forgets the tokens and nodes added so far, for programs that parse
+// more than once, like the language server
+func Reset() {
+	gic = &context{}
+}
+
 // Dump info to the console
 func Dump() {
 	fmt.Printf("Info:\n")
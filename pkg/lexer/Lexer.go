@@ -13,34 +13,43 @@ import (
 )
 
 var tokenTypeOverrides = map[string]TokenType{
-	"return":  TokReturn,
-	"if":      TokIf,
-	"else":    TokElse,
-	"for":     TokFor,
-	"while":   TokWhile,
-	"func":    TokFuncDefn,
-	"let":     TokLet,
-	"class":   TokClassDefn,
-	"include": TokDependency,
-	"link":    TokDependency,
-	"is":      TokNamespace,
-	"info":    TokInfo,
-	"as":      TokAs,
-	"true":    TokBool,
-	"false":   TokBool,
-	"nil":     TokNil,
-	"(":       TokLeftParen,
-	")":       TokRightParen,
-	"{":       TokLeftCurly,
-	"}":       TokRightCurly,
-	"[":       TokLeftBrace,
-	"]":       TokRightBrace,
-	"->":      TokRightArrow,
-	";":       TokSemiColon,
-	":":       TokNamespaceAccess,
-	"...":     TokElipsis,
-	".":       TokDot,
-	"?":       TokQuestionMark,
+	"return":   TokReturn,
+	"if":       TokIf,
+	"else":     TokElse,
+	"for":      TokFor,
+	"in":       TokIn,
+	"while":    TokWhile,
+	"break":    TokBreak,
+	"continue": TokContinue,
+	"match":    TokMatch,
+	"defer":    TokDefer,
+	"func":     TokFuncDefn,
+	"pure":     TokFuncDefn,
+	"let":      TokLet,
+	"const":    TokConst,
+	"class":    TokClassDefn,
+	"protocol": TokProtocolDefn,
+	"enum":     TokEnumDefn,
+	"include":  TokDependency,
+	"link":     TokDependency,
+	"is":       TokNamespace,
+	"info":     TokInfo,
+	"as":       TokAs,
+	"true":     TokBool,
+	"false":    TokBool,
+	"nil":      TokNil,
+	"(":        TokLeftParen,
+	")":        TokRightParen,
+	"{":        TokLeftCurly,
+	"}":        TokRightCurly,
+	"[":        TokLeftBrace,
+	"]":        TokRightBrace,
+	"->":       TokRightArrow,
+	";":        TokSemiColon,
+	":":        TokNamespaceAccess,
+	"...":      TokElipsis,
+	".":        TokDot,
+	"?":        TokQuestionMark,
 
 	"<-": TokOper,
 	":=": TokOper,
@@ -64,6 +73,7 @@ var tokenAliasOverrides = map[string]string{
 
 var defaultTypeNames = [...]string{
 	"bool", "byte", "short", "int", "long", "big", "large", "huge", "float", "string", "void",
+	"ubyte", "ushort", "uint", "ulong", "f16", "f32", "f64",
 }
 
 func getTokenValueAlias(value string) string {
@@ -83,9 +93,9 @@ type Lexer struct {
 	source     *Sourcefile
 	tokenCount int // the number of tokens lexed/emitted
 	line       int
-	col        int
 	pos        int // current position in input
 	start      int // beginning position of the current token
+	end        int // end position of the last token that was emitted
 	width      int // width of last rune read from input
 	input      string
 	tokens     []Token
@@ -164,14 +174,22 @@ func (l *Lexer) emit(typ TokenType) {
 		tok.Pos = int(l.start)
 		tok.EndPos = int(l.pos)
 		tok.Line = l.line
-		tok.Column = l.col
+		// Columns start at 1, at the first byte of the token
+		tok.Column = l.start - strings.LastIndexByte(l.input[:l.start], '\n')
 
 		newTyp, override := tokenTypeOverrides[tok.Value]
 		if override {
 			typ = newTyp
 		}
 
-		tok.SpaceBefore = true
+		// The whitespace between tokens is kept with the token after it, so
+		// the source can be written out again exactly as it was
+		tok.Leading = l.input[l.end:l.start]
+		tok.SpaceBefore = tok.Leading != ""
+		if len(l.tokens) > 0 {
+			l.tokens[len(l.tokens)-1].SpaceAfter = tok.SpaceBefore
+		}
+		l.end = l.pos
 
 		tok.Type = typ
 
@@ -198,9 +216,6 @@ func (l *Lexer) next() rune {
 	}
 	if r == '\n' {
 		l.line++
-		l.col = 1
-	} else {
-		l.col += width
 	}
 	return r
 }
@@ -257,6 +272,11 @@ func lexTopLevel(l *Lexer) stateFn {
 		l.backup()
 		return lexNumber
 
+	case r == ':' && l.peek() == '=':
+		l.next()
+		l.emit(TokOper)
+		return lexTopLevel
+
 	case r == ':':
 		// l.backup()
 		return lexSymbol
@@ -349,19 +369,11 @@ func lexIdentifer(l *Lexer) stateFn {
 	}
 }
 
+// lexSymbol emits a lone colon. Names that come after it, like the `n` in
+// `a[1:n]`, are lexed on their own.
 func lexSymbol(l *Lexer) stateFn {
-	for {
-		r := l.next()
-		if unicode.IsLetter(r) {
-			fmt.Println(l.value())
-		} else {
-
-			l.backup()
-
-			l.emit(TokSymbol)
-			return lexTopLevel
-		}
-	}
+	l.emit(TokSymbol)
+	return lexTopLevel
 }
 
 func lexNumber(l *Lexer) stateFn {
@@ -502,7 +514,6 @@ func isOnly(s string, r rune) bool {
 func NewLexer() *Lexer {
 	s := &Lexer{}
 	s.line = 1
-	s.col = 1
 	s.tokens = make([]Token, 0)
 	return s
 }
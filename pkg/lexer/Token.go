@@ -1,13 +1,13 @@
 package lexer
 
 import (
-	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
-	"github.com/geode-lang/geode/pkg/util/color"
+	"github.com/geode-lang/geode/pkg/diag"
 	"github.com/llir/llvm/ir/metadata"
 	"github.com/llir/llvm/ir/types"
 )
@@ -28,6 +28,9 @@ type Token struct {
 	Column      int       `json:"column"`
 	SpaceBefore bool      `json:"space_before"`
 	SpaceAfter  bool      `json:"space_after"`
+
+	// Leading is the whitespace between the token and the one before it
+	Leading string `json:"leading,omitempty"`
 }
 
 // Is - returns if the given token is in the set of types given
@@ -44,41 +47,74 @@ func (t Token) String() string {
 	return fmt.Sprintf("%s(%q)", t.Type.String(), t.Value)
 }
 
-// FileInfo returns the file address of a token
-func (t Token) FileInfo() string {
-	p := filepath.Clean(t.source.Path)
-	return fmt.Sprintf("%s:%d", p, t.Line)
+// Text returns the token as it is written in the source. The values of tokens
+// written with an alias, like ≠, are what the alias stands for instead
+func (t Token) Text() string {
+	if t.source == nil {
+		return t.Value
+	}
+	src := t.source.String()
+	if t.Pos > t.EndPos || t.EndPos > len(src) {
+		return t.Value
+	}
+	return src[t.Pos:t.EndPos]
 }
 
-// SyntaxError prints a formatted syntax error
-func (t *Token) SyntaxError() {
+// FileInfo returns the file address of a token. Files within the working
+// directory are given relative to it
+func (t Token) FileInfo() string {
+	return fmt.Sprintf("%s:%d", t.File(), t.Line)
+}
 
-	fmt.Println(t.SyntaxErrorS())
+// Path returns the path of the file the token is in
+func (t Token) Path() string {
+	if t.source == nil {
+		return ""
+	}
+	return t.source.Path
 }
 
-// SyntaxErrorS returns the string syntax error of a token
-func (t *Token) SyntaxErrorS() string {
-	if t.Type == TokError {
+// File returns the path of the file the token is in, relative to the working
+// directory if it is within it
+func (t Token) File() string {
+	if t.source == nil {
 		return ""
 	}
-	buf := &bytes.Buffer{}
-	src := t.source.String()
+	p := filepath.Clean(t.source.Path)
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, p); err == nil && !strings.HasPrefix(rel, "..") {
+			p = rel
+		}
+	}
+	return p
+}
 
-	// Highlight the source string at the error
-	src = src[:t.Pos] + color.Red(src[t.Pos:t.EndPos]) + src[t.EndPos:]
-	// Replace tabs with a fixed number of spaces
-	src = strings.Replace(src, "\t", "    ", -1)
-	lines := strings.Split(src, "\n")
-
-	location := fmt.Sprintf("%s:%d", t.source.Path, t.Line)
-	// Start printing
-	fmt.Fprintf(buf, "Syntax error: (%s)\n", location)
-	fmt.Fprintf(buf, color.Blue("   |\n"))
-
-	lineNumber := color.Red(fmt.Sprintf("%2d", t.Line))
-	fmt.Fprintf(buf, "%s %s %s\n", lineNumber, color.Blue("|"), strings.TrimSpace(lines[t.Line-1]))
-	fmt.Fprintf(buf, color.Blue("   |\n"))
-	return buf.String()
+// Span returns the place in the source the token is at, for diagnostics. Spans
+// of tokens that run over more than one line end at the end of the first
+func (t Token) Span() diag.Span {
+	span := diag.Span{
+		File:      t.File(),
+		Line:      t.Line,
+		Column:    t.Column,
+		EndColumn: t.Column + t.EndPos - t.Pos,
+	}
+	if t.source == nil || t.Type == TokError {
+		return span
+	}
+	src := t.source.String()
+	if t.Pos > len(src) {
+		return span
+	}
+	start := strings.LastIndexByte(src[:t.Pos], '\n') + 1
+	end := strings.IndexByte(src[start:], '\n')
+	if end < 0 {
+		end = len(src) - start
+	}
+	span.Source = src[start : start+end]
+	if span.EndColumn > end+1 {
+		span.EndColumn = end + 1
+	}
+	return span
 }
 
 // InferType takes some token and guesses the type
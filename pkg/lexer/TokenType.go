@@ -59,14 +59,22 @@ const (
 	TokQuestionMark
 
 	TokFor
+	TokIn
 	TokWhile
 	TokIf
 	TokElse
 	TokReturn
+	TokBreak
+	TokContinue
+	TokMatch
+	TokDefer
 	TokFuncDefn
 	TokClassDefn
+	TokProtocolDefn
+	TokEnumDefn
 	TokNamespace
 	TokLet
+	TokConst
 	TokAs
 	TokNil
 
@@ -23,6 +23,7 @@ type Sourcefile struct {
 func NewSourcefile(name string) (*Sourcefile, error) {
 	s := &Sourcefile{}
 	s.Name = name
+	s.Path = name
 	return s, nil
 }
 
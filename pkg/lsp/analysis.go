@@ -0,0 +1,145 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/geode-lang/geode/pkg/arg"
+	"github.com/geode-lang/geode/pkg/ast"
+	"github.com/geode-lang/geode/pkg/diag"
+	"github.com/geode-lang/geode/pkg/info"
+	"github.com/geode-lang/geode/pkg/lexer"
+	"github.com/geode-lang/geode/pkg/util/log"
+)
+
+// analysis is what the compiler knows about a document, from the last time it
+// was compiled
+type analysis struct {
+	uri    string
+	path   string
+	prog   *ast.Program
+	errs   diag.List
+	tokens []lexer.Token
+	names  []ast.NameRef // the variables the names in the document refer to
+	files  map[string]*file
+}
+
+// fatalError is what a fatal error of the compiler panics with while a
+// document is analyzed
+type fatalError string
+
+// analyze compiles the package of a document like a build would, but with the
+// text of the documents that are open in place of the files on disk. Every
+// function in the document is compiled, so the errors in them are found
+// without a main function
+func analyze(uri, path, text string, overlay map[string]string, triple string) (a *analysis) {
+	a = &analysis{uri: uri, path: path, files: make(map[string]*file)}
+
+	src, _ := lexer.NewSourcefile(path)
+	src.LoadString(text)
+	a.tokens = lexer.Lex(src)
+
+	info.Reset()
+	prog := ast.NewProgram()
+	prog.Overlay = overlay
+	prog.SetTarget(triple)
+	prog.RecordNames()
+	a.prog = prog
+
+	// The compiler exits on the errors it can't go on from, and those have to
+	// be reported instead
+	previousHandler := log.FatalHandler
+	log.FatalHandler = func(msg string) { panic(fatalError(msg)) }
+	defer func() {
+		log.FatalHandler = previousHandler
+		if r := recover(); r != nil {
+			msg, isFatal := r.(fatalError)
+			if !isFatal {
+				msg = fatalError(fmt.Sprintf("internal compiler error: %v", r))
+			}
+			a.errs.Add(diag.Errorf(diag.Span{}, "%s", strings.TrimRight(string(msg), "\n")))
+		}
+		a.collectNames()
+	}()
+
+	if !*arg.DisableRuntime {
+		prog.ParseDep("", "runtime")
+	}
+	prog.ParseText(text, path)
+	prog.ParsePath(path)
+
+	if _, err := prog.Congeal(); err != nil {
+		a.errs.Add(err)
+		return a
+	}
+	if err := prog.Check(path); err != nil {
+		a.errs.Add(err)
+	}
+	return a
+}
+
+// collectNames keeps the names that were recorded in the document, in the order
+// they are in it
+func (a *analysis) collectNames() {
+	for _, ref := range a.prog.Names {
+		if ref.Token.Path() == a.path {
+			a.names = append(a.names, ref)
+		}
+	}
+	sort.SliceStable(a.names, func(i, j int) bool {
+		return a.names[i].Token.Pos < a.names[j].Token.Pos
+	})
+}
+
+// diagnostics returns the errors found in the document. Errors that aren't in
+// it, like ones in the packages it depends on, are shown at the start of it
+func (a *analysis) diagnostics(src *source) []Diagnostic {
+	diags := make([]Diagnostic, 0, len(a.errs))
+	for _, d := range a.errs {
+		lsp := Diagnostic{
+			Severity: severityError,
+			Source:   "geode",
+			Message:  d.Message,
+		}
+		switch d.Severity {
+		case diag.Warning:
+			lsp.Severity = severityWarning
+		case diag.Note:
+			lsp.Severity = severityInformation
+		}
+
+		if d.Span != nil && a.inDocument(*d.Span) {
+			lsp.Range = src.span(d.Span.Line, d.Span.Column, d.Span.EndColumn)
+		} else if d.Span != nil {
+			lsp.Message = d.Error()
+		}
+
+		for _, note := range d.Notes {
+			lsp.Message += "\nnote: " + note
+		}
+		for _, s := range d.Suggestions {
+			lsp.Message += "\nhelp: " + s.Message
+		}
+		for _, label := range d.Labels {
+			if a.inDocument(label) {
+				lsp.RelatedInformation = append(lsp.RelatedInformation, DiagnosticRelatedInformation{
+					Location: Location{a.uri, src.span(label.Line, label.Column, label.EndColumn)},
+					Message:  label.Label,
+				})
+			}
+		}
+		diags = append(diags, lsp)
+	}
+	return diags
+}
+
+// inDocument returns if a span is in the document that was analyzed
+func (a *analysis) inDocument(span diag.Span) bool {
+	if span.Line == 0 {
+		return false
+	}
+	abs, err := filepath.Abs(span.File)
+	return err == nil && abs == a.path
+}
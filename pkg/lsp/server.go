@@ -0,0 +1,238 @@
+// Package lsp is a language server for Geode, which editors talk to over stdio
+// with the language server protocol. Documents are compiled as they change,
+// with the text the editor has for them rather than what is saved, and what the
+// compiler finds out about them answers the editor's requests
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/geode-lang/geode/pkg/util/color"
+)
+
+// document is a file the editor has open
+type document struct {
+	uri      string
+	path     string
+	text     string
+	version  int
+	analysis *analysis
+}
+
+// server answers the requests of one editor
+type server struct {
+	conn     *conn
+	triple   string
+	docs     map[string]*document
+	shutdown bool
+}
+
+// Serve talks to an editor until it asks the server to exit, and returns the
+// code to exit with. Messages are handled in the order they are sent
+func Serve(in io.Reader, out io.Writer, triple string) int {
+	color.Disable()
+	s := &server{
+		conn:   newConn(in, out),
+		triple: triple,
+		docs:   make(map[string]*document),
+	}
+
+	for {
+		msg, err := s.conn.read()
+		if msg == nil && err != nil {
+			// The editor went away without asking the server to exit
+			return 1
+		}
+		if err != nil {
+			s.replyError(nil, codeParseError, err.Error())
+			continue
+		}
+		if msg.Method == "exit" {
+			if s.shutdown {
+				return 0
+			}
+			return 1
+		}
+
+		result, err := s.handle(msg)
+		if msg.ID == nil {
+			continue
+		}
+		if err != nil {
+			code := codeInvalidParams
+			if rpcErr, isRPCErr := err.(responseError); isRPCErr {
+				code = rpcErr.Code
+			}
+			s.replyError(msg.ID, code, err.Error())
+			continue
+		}
+		s.conn.write(response{"2.0", msg.ID, result})
+	}
+}
+
+func (s *server) replyError(id *json.RawMessage, code int, msg string) {
+	s.conn.write(errorResponse{"2.0", id, responseError{code, msg}})
+}
+
+func (s *server) notify(method string, params interface{}) {
+	s.conn.write(notification{"2.0", method, params})
+}
+
+// handle runs a request or notification, and returns the result to reply with
+func (s *server) handle(msg *message) (interface{}, error) {
+	switch msg.Method {
+	case "initialize":
+		return InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:       syncFull,
+				HoverProvider:          true,
+				DefinitionProvider:     true,
+				CompletionProvider:     &CompletionOptions{TriggerCharacters: []string{":"}},
+				DocumentSymbolProvider: true,
+			},
+			ServerInfo: ServerInfo{"geode"},
+		}, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		params := DidOpenTextDocumentParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		item := params.TextDocument
+		s.docs[item.URI] = &document{
+			uri:     item.URI,
+			path:    uriToPath(item.URI),
+			text:    item.Text,
+			version: item.Version,
+		}
+		s.update(s.docs[item.URI])
+		return nil, nil
+
+	case "textDocument/didChange":
+		params := DidChangeTextDocumentParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		doc, err := s.document(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range params.ContentChanges {
+			if change.Range == nil {
+				doc.text = change.Text
+				continue
+			}
+			src := newSource(doc.text)
+			doc.text = doc.text[:src.offset(change.Range.Start)] + change.Text + doc.text[src.offset(change.Range.End):]
+		}
+		doc.version = params.TextDocument.Version
+		s.update(doc)
+		return nil, nil
+
+	case "textDocument/didSave":
+		params := DidSaveTextDocumentParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		doc, err := s.document(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		if params.Text != nil {
+			doc.text = *params.Text
+		}
+		s.update(doc)
+		return nil, nil
+
+	case "textDocument/didClose":
+		params := DidCloseTextDocumentParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{params.TextDocument.URI, []Diagnostic{}})
+		return nil, nil
+
+	case "textDocument/hover":
+		doc, pos, err := s.position(msg)
+		if err != nil {
+			return nil, err
+		}
+		if hover := doc.analysis.hover(newSource(doc.text), pos); hover != nil {
+			return hover, nil
+		}
+		return nil, nil
+
+	case "textDocument/definition":
+		doc, pos, err := s.position(msg)
+		if err != nil {
+			return nil, err
+		}
+		if loc := doc.analysis.definition(newSource(doc.text), pos); loc != nil {
+			return loc, nil
+		}
+		return nil, nil
+
+	case "textDocument/completion":
+		doc, pos, err := s.position(msg)
+		if err != nil {
+			return nil, err
+		}
+		return doc.analysis.completion(newSource(doc.text), pos), nil
+
+	case "textDocument/documentSymbol":
+		params := DocumentSymbolParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		doc, err := s.document(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return doc.analysis.symbols(newSource(doc.text)), nil
+
+	case "initialized", "$/cancelRequest":
+		return nil, nil
+	}
+	return nil, responseError{codeMethodNotFound, fmt.Sprintf("method %q is not supported", msg.Method)}
+}
+
+// document returns an open document
+func (s *server) document(uri string) (*document, error) {
+	doc, found := s.docs[uri]
+	if !found {
+		return nil, fmt.Errorf("document %s is not open", uri)
+	}
+	return doc, nil
+}
+
+// position returns the document and position a request is about
+func (s *server) position(msg *message) (*document, Position, error) {
+	params := TextDocumentPositionParams{}
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return nil, Position{}, err
+	}
+	doc, err := s.document(params.TextDocument.URI)
+	return doc, params.Position, err
+}
+
+// update compiles a document again and sends the editor the errors in it.
+// The other documents that are open are compiled with the text the editor has
+// for them, in case they are in the same package
+func (s *server) update(doc *document) {
+	overlay := make(map[string]string, len(s.docs))
+	for _, other := range s.docs {
+		overlay[other.path] = other.text
+	}
+	doc.analysis = analyze(doc.uri, doc.path, doc.text, overlay, s.triple)
+	s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Diagnostics: doc.analysis.diagnostics(newSource(doc.text)),
+	})
+}
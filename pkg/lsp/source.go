@@ -0,0 +1,104 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// source is the text of a file, split into lines to convert between the byte
+// columns of the compiler and the positions of the protocol
+type source struct {
+	lines []string
+}
+
+func newSource(text string) *source {
+	return &source{strings.Split(text, "\n")}
+}
+
+// line returns a line of the source, where the first line is 1
+func (s *source) line(line int) string {
+	if line < 1 || line > len(s.lines) {
+		return ""
+	}
+	return strings.TrimSuffix(s.lines[line-1], "\r")
+}
+
+// position returns the position of a line and byte column, which both start at
+// 1 as they do in tokens
+func (s *source) position(line, column int) Position {
+	if line < 1 {
+		return Position{}
+	}
+	text := s.line(line)
+	if column-1 > len(text) {
+		column = len(text) + 1
+	}
+	if column < 1 {
+		column = 1
+	}
+	return Position{line - 1, utf16Len(text[:column-1])}
+}
+
+// span returns the range of a line from one byte column up to another
+func (s *source) span(line, column, endColumn int) Range {
+	start := s.position(line, column)
+	end := s.position(line, endColumn)
+	if end.Character < start.Character {
+		end = start
+	}
+	return Range{start, end}
+}
+
+// column returns the line and byte column of a position, which both start at 1
+func (s *source) column(pos Position) (int, int) {
+	text := s.line(pos.Line + 1)
+	units := 0
+	for i, r := range text {
+		if units >= pos.Character {
+			return pos.Line + 1, i + 1
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+	return pos.Line + 1, len(text) + 1
+}
+
+// offset returns the byte offset of a position in the text
+func (s *source) offset(pos Position) int {
+	line, column := s.column(pos)
+	offset := 0
+	for i := 0; i < line-1 && i < len(s.lines); i++ {
+		offset += len(s.lines[i]) + 1
+	}
+	return offset + column - 1
+}
+
+func utf16Len(text string) int {
+	n := 0
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+		n += len(utf16.Encode([]rune{r}))
+	}
+	return n
+}
+
+// uriToPath returns the path of a file:// URI
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.Clean(u.Path)
+}
+
+// pathToURI returns the file:// URI of a path
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
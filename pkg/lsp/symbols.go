@@ -0,0 +1,374 @@
+package lsp
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/geode-lang/geode/pkg/ast"
+	"github.com/geode-lang/geode/pkg/lexer"
+)
+
+// decl is something declared at the top level of a package
+type decl struct {
+	name   string
+	kind   int    // the SymbolKind of the declaration
+	detail string // the declaration without it's body, to show in hovers
+	node   ast.Node
+	start  lexer.Token // the token the declaration starts with
+	tok    lexer.Token // the name it is declared with
+}
+
+// file is the text and tokens of a file the program parsed
+type file struct {
+	src    *source
+	tokens []lexer.Token
+}
+
+// file returns the text and tokens of a file of the program
+func (a *analysis) file(path string) *file {
+	if f, found := a.files[path]; found {
+		return f
+	}
+	f := &file{src: newSource("")}
+	if pkg, found := a.prog.Packages[path]; found {
+		if sf, found := pkg.Files[path]; found {
+			f.src = newSource(sf.String())
+			f.tokens = lexer.Lex(sf)
+		}
+	}
+	if path == a.path {
+		f.tokens = a.tokens
+	}
+	a.files[path] = f
+	return f
+}
+
+// nameAfter returns the first name token in a file at or after another token.
+// Declarations only know where they start, and their name is the first name in
+// them
+func (a *analysis) nameAfter(path string, start lexer.Token, name string) lexer.Token {
+	for _, tok := range a.file(path).tokens {
+		if tok.Pos >= start.Pos && tok.Is(lexer.TokIdent, lexer.TokType) && tok.Value == name {
+			return tok
+		}
+	}
+	return start
+}
+
+// decls returns what is declared in a file of the program, in the order it is
+// declared in
+func (a *analysis) decls(path string) []decl {
+	pkg, found := a.prog.Packages[path]
+	if !found {
+		return nil
+	}
+
+	decls := make([]decl, 0, len(pkg.Nodes))
+	for _, node := range pkg.Nodes {
+		d := decl{node: node}
+		switch n := node.(type) {
+		case ast.FunctionNode:
+			d.name, d.kind, d.detail, d.start, d.tok = n.Name.String(), symbolFunction, n.Signature(), n.Token, n.Name.Token
+		case ast.ClassNode:
+			d.name, d.kind, d.detail, d.start = n.Name, symbolClass, classDetail(n), n.Token
+		case ast.ProtocolNode:
+			d.name, d.kind, d.detail, d.start = n.Name, symbolInterface, n.String(), n.Token
+		case ast.EnumNode:
+			d.name, d.kind, d.detail, d.start = n.Name, symbolEnum, n.String(), n.Token
+		case ast.ConstNode:
+			d.name, d.kind, d.detail, d.start = n.Name, symbolConstant, n.String(), n.Token
+		case ast.GlobalVariableDeclNode:
+			d.name, d.kind, d.detail, d.start, d.tok = n.Name.String(), symbolVariable, fmt.Sprintf("%s %s", n.Type, n.Name), n.Token, n.Name.Token
+		default:
+			continue
+		}
+		if d.tok.Line == 0 {
+			d.tok = a.nameAfter(path, d.start, d.name)
+		}
+		decls = append(decls, d)
+	}
+	return decls
+}
+
+// classDetail returns the fields and method signatures of a class
+func classDetail(n ast.ClassNode) string {
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "class %s", n.Name)
+	if len(n.TypeParams) > 0 {
+		fmt.Fprintf(buff, "<%s>", strings.Join(n.TypeParams, ", "))
+	}
+	fmt.Fprintf(buff, " {\n")
+	for _, v := range n.Variables {
+		fmt.Fprintf(buff, "\t%s %s\n", v.Typ, v.Name)
+	}
+	for _, m := range n.Methods {
+		fmt.Fprintf(buff, "\t%s\n", m.Signature())
+	}
+	fmt.Fprintf(buff, "}")
+	return buff.String()
+}
+
+// packageFiles returns the files of the packages with a name, in order
+func (a *analysis) packageFiles(name string) []string {
+	paths := make([]string, 0)
+	for path, pkg := range a.prog.Packages {
+		if pkg.Name == name {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// packageName returns the name of the package the document is in
+func (a *analysis) packageName() string {
+	if pkg, found := a.prog.Packages[a.path]; found {
+		return pkg.Name
+	}
+	return ""
+}
+
+// lookup finds the declaration a name refers to. Names without a package are
+// looked for in the document's package and then the runtime
+func (a *analysis) lookup(name string) *decl {
+	ns, nm := ast.ParseName(name)
+	packages := []string{ns}
+	if ns == "" {
+		packages = []string{a.packageName(), "runtime"}
+	}
+	for _, pkg := range packages {
+		for _, path := range a.packageFiles(pkg) {
+			for _, d := range a.decls(path) {
+				if d.name == nm {
+					return &d
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// tokenAt returns the name token at a position in the document
+func (a *analysis) tokenAt(src *source, pos Position) (lexer.Token, bool) {
+	line, column := src.column(pos)
+	for _, tok := range a.tokens {
+		if tok.Line == line && tok.Column <= column && column < tok.Column+tok.EndPos-tok.Pos {
+			return tok, tok.Is(lexer.TokIdent, lexer.TokType)
+		}
+	}
+	return lexer.Token{}, false
+}
+
+// nameRef returns what the variable a name token refers to
+func (a *analysis) nameRef(tok lexer.Token) (ast.NameRef, bool) {
+	for _, ref := range a.names {
+		if ref.Token.Pos == tok.Pos {
+			return ref, true
+		}
+	}
+	return ast.NameRef{}, false
+}
+
+// location returns where a token is
+func (a *analysis) location(tok lexer.Token) Location {
+	path := tok.Path()
+	span := a.file(path).src.span(tok.Line, tok.Column, tok.Column+tok.EndPos-tok.Pos)
+	if path == a.path {
+		return Location{a.uri, span}
+	}
+	return Location{pathToURI(path), span}
+}
+
+// hover describes the name at a position. Variables are shown with their type,
+// and what is declared in packages with it's declaration
+func (a *analysis) hover(src *source, pos Position) *Hover {
+	tok, isName := a.tokenAt(src, pos)
+	if !isName {
+		return nil
+	}
+	text := ""
+	if ref, found := a.nameRef(tok); found && ref.Type != "" {
+		text = fmt.Sprintf("%s %s", ref.Type, tok.Value)
+	} else if d := a.lookup(tok.Value); d != nil {
+		text = d.detail
+	} else {
+		return nil
+	}
+	r := src.span(tok.Line, tok.Column, tok.Column+tok.EndPos-tok.Pos)
+	return &Hover{
+		Contents: MarkupContent{"markdown", fmt.Sprintf("```geode\n%s\n```", text)},
+		Range:    &r,
+	}
+}
+
+// definition returns where the name at a position is defined
+func (a *analysis) definition(src *source, pos Position) *Location {
+	tok, isName := a.tokenAt(src, pos)
+	if !isName {
+		return nil
+	}
+	if ref, found := a.nameRef(tok); found && ref.Def.Line > 0 {
+		loc := a.location(ref.Def)
+		return &loc
+	}
+	if d := a.lookup(tok.Value); d != nil {
+		loc := a.location(d.tok)
+		return &loc
+	}
+	return nil
+}
+
+// completionKinds are the CompletionItemKinds of the kinds of declarations
+var completionKinds = map[int]int{
+	symbolFunction:  completionFunction,
+	symbolClass:     completionClass,
+	symbolInterface: completionInterface,
+	symbolEnum:      completionEnum,
+	symbolConstant:  completionConstant,
+	symbolVariable:  completionVariable,
+}
+
+// completion returns the names that can be written at a position. Names with a
+// package in front of them complete to the members of it, and other names to
+// the variables in scope, what the document's package declares, and the
+// packages it depends on
+func (a *analysis) completion(src *source, pos Position) *CompletionList {
+	line, column := src.column(pos)
+	text := src.line(line)[:column-1]
+	start := len(text)
+	for start > 0 && isNameByte(text[start-1]) {
+		start--
+	}
+	prefix := text[start:]
+
+	items := make([]CompletionItem, 0)
+	addDecls := func(pkg string) {
+		for _, path := range a.packageFiles(pkg) {
+			for _, d := range a.decls(path) {
+				items = append(items, CompletionItem{d.name, completionKinds[d.kind], d.detail})
+			}
+		}
+	}
+
+	if i := strings.LastIndexByte(prefix, ':'); i >= 0 {
+		addDecls(prefix[:i])
+		prefix = prefix[i+1:]
+	} else {
+		// Variables defined earlier in the declaration the position is in
+		offset := src.offset(pos)
+		scopeStart := 0
+		for _, d := range a.decls(a.path) {
+			if d.start.Pos <= offset {
+				scopeStart = d.start.Pos
+			}
+		}
+		for _, ref := range a.names {
+			if ref.Token == ref.Def && ref.Token.Pos >= scopeStart && ref.Token.Pos < offset {
+				items = append(items, CompletionItem{ref.Token.Value, completionVariable, ref.Type})
+			}
+		}
+
+		addDecls(a.packageName())
+		if pkg, found := a.prog.Packages[a.path]; found {
+			for _, dep := range pkg.DependencyPaths {
+				for path, other := range a.prog.Packages {
+					if filepath.Dir(path) == dep {
+						items = append(items, CompletionItem{other.Name, completionModule, ""})
+					}
+				}
+			}
+		}
+	}
+
+	// Only the first of the items with the same name is kept, as it is the
+	// one that is in the closest scope
+	seen := map[string]bool{}
+	matches := make([]CompletionItem, 0, len(items))
+	for _, item := range items {
+		if !seen[item.Label] && strings.HasPrefix(item.Label, prefix) {
+			seen[item.Label] = true
+			matches = append(matches, item)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Label < matches[j].Label
+	})
+	return &CompletionList{Items: matches}
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c == ':' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// symbols returns what is declared in the document, for outlines. Declarations
+// range up to the one after them
+func (a *analysis) symbols(src *source) []DocumentSymbol {
+	decls := a.decls(a.path)
+	symbols := make([]DocumentSymbol, 0, len(decls))
+	for i, d := range decls {
+		end := lexer.Token{}
+		for _, tok := range a.tokens {
+			if i+1 < len(decls) && tok.Pos >= decls[i+1].start.Pos {
+				break
+			}
+			end = tok
+		}
+		sym := DocumentSymbol{
+			Name:           d.name,
+			Detail:         d.detail,
+			Kind:           d.kind,
+			Range:          a.between(src, d.start, end),
+			SelectionRange: a.between(src, d.tok, d.tok),
+		}
+
+		switch n := d.node.(type) {
+		case ast.ClassNode:
+			sym.Detail = ""
+			for _, v := range n.Variables {
+				sym.Children = append(sym.Children, DocumentSymbol{
+					Name:           v.Name.String(),
+					Detail:         v.Typ.String(),
+					Kind:           symbolField,
+					Range:          a.between(src, v.Token, v.Name.Token),
+					SelectionRange: a.between(src, v.Name.Token, v.Name.Token),
+				})
+			}
+			for _, m := range n.Methods {
+				sym.Children = append(sym.Children, DocumentSymbol{
+					Name:           m.Name.String(),
+					Detail:         m.Signature(),
+					Kind:           symbolMethod,
+					Range:          a.between(src, m.Token, m.Name.Token),
+					SelectionRange: a.between(src, m.Name.Token, m.Name.Token),
+				})
+			}
+		case ast.EnumNode:
+			sym.Detail = ""
+			for _, v := range n.Variants {
+				sym.Children = append(sym.Children, DocumentSymbol{
+					Name:           v.Name,
+					Detail:         v.String(),
+					Kind:           symbolEnumMember,
+					Range:          a.between(src, v.Token, v.Token),
+					SelectionRange: a.between(src, v.Token, v.Token),
+				})
+			}
+		}
+		symbols = append(symbols, sym)
+	}
+	return symbols
+}
+
+// between returns the range from the start of one token to the end of another
+func (a *analysis) between(src *source, start, end lexer.Token) Range {
+	if end.Pos < start.Pos {
+		end = start
+	}
+	return Range{
+		Start: src.position(start.Line, start.Column),
+		End:   src.position(end.Line, end.Column+end.EndPos-end.Pos),
+	}
+}
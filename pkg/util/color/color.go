@@ -37,6 +37,19 @@ func init() {
 	}
 }
 
+// Disable turns coloring off, for output that isn't shown in a terminal
+func Disable() {
+	TEXT_RESET = ""
+	TEXT_BOLD = ""
+	TEXT_RED = ""
+	TEXT_GREEN = ""
+	TEXT_YELLOW = ""
+	TEXT_BLUE = ""
+	TEXT_MAGENTA = ""
+	TEXT_CYAN = ""
+	TEXT_WHITE = ""
+}
+
 func Bold(s string) string {
 	return TEXT_BOLD + s + TEXT_RESET
 }
@@ -60,8 +60,16 @@ func Error(format string, args ...interface{}) {
 	log(tolog)
 }
 
+// FatalHandler is called with the message of a fatal error instead of exiting,
+// when it is set. Programs that have to keep running after an error, like the
+// language server, use it to recover from them
+var FatalHandler func(msg string)
+
 // Fatal -
 func Fatal(format string, args ...interface{}) {
+	if FatalHandler != nil {
+		FatalHandler(fmt.Sprintf(format, args...))
+	}
 	tolog := color.Red("[fatal] ") + fmt.Sprintf(format, args...)
 	log(tolog)
 	os.Exit(1)
@@ -182,34 +182,34 @@ func EatError(e error) {
 	}
 }
 
-// Jaro returns the jaro distance between two strings
+// Jaro returns the jaro similarity of two strings, from 0 when they have
+// nothing in common to 1 when they are the same
 func Jaro(a, b string) float64 {
 	la := float64(len(a))
 	lb := float64(len(b))
+	if la == 0 || lb == 0 {
+		return 0
+	}
 
-	// match range = max(len(a), len(b)) / 2 - 1
-	matchRange := int(math.Floor(math.Max(la, lb)/2.0)) - 1
-	matchRange = int(math.Max(0, float64(matchRange-1)))
-	var matches, halfs float64
-	transposed := make([]bool, len(b))
+	// Characters match if they are the same and no further apart than
+	// max(len(a), len(b)) / 2 - 1
+	matchRange := int(math.Max(0, math.Floor(math.Max(la, lb)/2.0)-1))
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+	var matches float64
 
 	for i := 0; i < len(a); i++ {
 		start := int(math.Max(0, float64(i-matchRange)))
 		end := int(math.Min(lb-1, float64(i+matchRange)))
 
 		for j := start; j <= end; j++ {
-			if transposed[j] {
+			if matchedB[j] || a[i] != b[j] {
 				continue
 			}
-
-			if a[i] == b[j] {
-				if i != j {
-					halfs++
-				}
-				matches++
-				transposed[j] = true
-				break
-			}
+			matchedA[i] = true
+			matchedB[j] = true
+			matches++
+			break
 		}
 	}
 
@@ -217,7 +217,22 @@ func Jaro(a, b string) float64 {
 		return 0
 	}
 
-	transposes := math.Floor(float64(halfs / 2))
+	// Half of the matching characters that are out of order are transpositions
+	var halfs float64
+	j := 0
+	for i := 0; i < len(a); i++ {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if a[i] != b[j] {
+			halfs++
+		}
+		j++
+	}
+	transposes := math.Floor(halfs / 2)
 
 	return ((matches / la) + (matches / lb) + (matches-transposes)/matches) / 3.0
 }
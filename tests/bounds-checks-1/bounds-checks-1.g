@@ -0,0 +1,18 @@
+is main
+
+include "io"
+
+func at(int[] s, int i) int = s[i]
+
+func main int {
+	nums = [1, 2, 3]
+	for i = 0; i < 3; i += 1 {
+		io:print("%d ", nums[i])
+	}
+	io:print("\n")
+
+	int[] s = [10, 20, 30]
+	io:print("%d %d\n", at(s, 0), at(s, 2))
+	io:print("%d\n", at(s[1:], 2))
+	return 0
+}
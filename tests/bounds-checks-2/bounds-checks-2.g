@@ -0,0 +1,6 @@
+is main
+
+func main int {
+	nums = [1, 2, 3]
+	return nums[3]
+}
@@ -0,0 +1,11 @@
+is main
+
+include "io"
+
+func main int {
+	int[] s = [1, 2, 3, 4]
+	short = s[:2]
+	# The storage of the slice goes past it's length
+	io:print("%d\n", short[3])
+	return 0
+}
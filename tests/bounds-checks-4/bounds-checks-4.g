@@ -0,0 +1,20 @@
+is main
+
+include "io"
+
+func main int {
+	a = [1, 2, 3]
+	if false {
+		a = [9]
+	}
+	# The assignment in the if never runs, so a still has 3 elements
+	io:print("%d\n", a[2])
+
+	b = [4, 5]
+	for int i = 0; i < 2; i += 1 {
+		io:print("%d ", b[1])
+		b = [6, 7, 8]
+	}
+	io:print("%d\n", b[2])
+	return 0
+}
@@ -0,0 +1,18 @@
+is main
+include "io"
+
+func sum(long* a, int n) long {
+	long s = 0
+	for int i = 0; i < n; i += 1 {
+		s += a[i]
+	}
+	return s
+}
+
+func main int {
+	nums = [1, 2, 3]
+	i = 7
+	sum(nums, 3)
+	io:print("%d\n", nums[i])
+	return 0
+}
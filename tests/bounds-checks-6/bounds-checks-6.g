@@ -0,0 +1,13 @@
+is main
+include "io"
+
+func indexes() (int, int) = 7, 1
+
+func main int {
+	# Declarations with a type know the length of the array too
+	int* a = [1, 2, 3]
+	q, r := indexes()
+	io:print("%d\n", a[r])
+	io:print("%d\n", a[q])
+	return 0
+}
@@ -0,0 +1,48 @@
+is main
+include "io"
+
+func main int {
+	# stop at the first multiple of 7
+	for int i = 1; i < 100; i = i + 1 {
+		if i % 7 == 0 {
+			io:print("first %d\n", i);
+			break;
+		}
+	}
+
+	# only print odd numbers
+	int n = 0;
+	while n < 6 {
+		n = n + 1;
+		if n % 2 == 0 {
+			continue;
+		}
+		io:print("%d ", n);
+	}
+	io:print("\n");
+
+	# find a pair that sums to 10 with both above 3
+	outer: for int a = 0; a < 10; a = a + 1 {
+		for int b = 0; b < 10; b = b + 1 {
+			if b > a {
+				continue outer;
+			}
+			if a + b == 10 && b > 3 {
+				io:print("%d %d\n", a, b);
+				break outer;
+			}
+		}
+	}
+
+	int count = 0;
+	rows: while count < 100 {
+		count = count + 1;
+		for int j = 0; j < 3; j = j + 1 {
+			if count == 4 {
+				break rows;
+			}
+		}
+	}
+	io:print("%d\n", count);
+	return 0;
+}
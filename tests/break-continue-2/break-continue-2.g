@@ -0,0 +1,6 @@
+is main
+
+func main int {
+	break;
+	return 0;
+}
@@ -0,0 +1,43 @@
+is main
+include "io"
+
+func make_counter(int start) func() int {
+	int count = start;
+	return func() int {
+		count = count + 1;
+		return count;
+	};
+}
+
+func apply(func(int) int f, int x) int = f(x);
+
+class Button {
+	string label;
+	func(string) on_click;
+}
+
+func main int {
+	int n = 10;
+	func(int) int add = func(int x) int = x + n;
+	io:print("%d\n", add(5));
+	io:print("%d\n", apply(func(int x) int = x * 2, 21));
+
+	func() int next = make_counter(3);
+	next();
+	io:print("%d\n", next());
+
+	int base = 100;
+	func(int) func(int) int adder = func(int a) func(int) int {
+		return func(int b) int = base + a + b;
+	};
+	func(int) int add7 = adder(7);
+	io:print("%d\n", add7(1));
+
+	Button b;
+	b.label = "ok";
+	b.on_click = func(string s) {
+		io:print("clicked %s %d\n", s, n);
+	};
+	b.on_click(b.label);
+	return 0;
+}
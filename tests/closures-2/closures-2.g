@@ -0,0 +1,25 @@
+is main
+include "io"
+
+func main int {
+	int lo = 2;
+	int hi = 8;
+	# Closures with branches in their bodies leave the blocks they end in
+	# on the compiler, which must not leak into the function around them
+	func(int) bool inside = func(int x) bool = x >= lo && x <= hi;
+	func(int) bool outside = func(int x) bool {
+		if x < lo || x > hi {
+			return true;
+		}
+		return false;
+	};
+	for i = 0; i < 10; i += 3 {
+		if inside(i) {
+			io:print("%d inside\n", i);
+		}
+		if outside(i) {
+			io:print("%d outside\n", i);
+		}
+	}
+	return 0;
+}
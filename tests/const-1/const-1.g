@@ -0,0 +1,51 @@
+is main
+
+include "io"
+include "str"
+include "math"
+
+const KB = 1024
+const SIZE = 64 * KB
+const WIDE = 1 << 70 >> 60
+const byte MASK = 0x7f
+const NAME = "geo" + "de"
+const HALF = 1.0 / 2
+const VERBOSE = SIZE > 1000 && !false
+const NEG = -3
+const COUNT = 4
+
+class Buffer(n) {
+	int[COUNT] data
+	int n
+}
+
+func main int {
+	io:print("%d %d %d %d\n", KB, SIZE, WIDE, MASK)
+	io:print("%s %g %v %d\n", NAME, HALF, VERBOSE, NEG)
+	io:print("%.5f\n", math:PI * 2)
+
+	int[COUNT * 2] squares
+	for i = 0; i < len(squares); i += 1 {
+		squares[i] = i * i
+	}
+	for x in squares {
+		io:print("%d ", x)
+	}
+	io:print("\n")
+
+	b = Buffer(1)
+	b.data[COUNT - 1] = 42
+	io:print("%d %d\n", b.data[3], info(int[COUNT]).size)
+
+	x = 65536
+	match x {
+		KB -> io:print("kb\n")
+		SIZE -> io:print("size\n")
+		_ -> io:print("other\n")
+	}
+	match "geode" {
+		NAME -> io:print("name\n")
+		_ -> io:print("other\n")
+	}
+	return 0
+}
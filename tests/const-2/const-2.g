@@ -0,0 +1,8 @@
+is main
+
+const LIMIT = 200 + 100
+
+func main int {
+	byte b = LIMIT
+	return 0
+}
@@ -0,0 +1,7 @@
+is main
+
+func main int {
+	# Integer literals are checked like constants are
+	byte b = 300
+	return 0
+}
@@ -0,0 +1,37 @@
+is main
+include "io"
+
+class Point(x, y) {
+	int x;
+	int y;
+	func sum int = this.x + this.y;
+}
+
+class Counter {
+	int count;
+	string label;
+	func init(string label, int start) {
+		this.label = label;
+		this.count = start * 2;
+	}
+}
+
+class Both(a) {
+	int a;
+	int b;
+	func init {
+		this.b = this.a + 1;
+	}
+}
+
+func main int {
+	Point p = Point(3, 4);
+	io:print("%d %d %d\n", p.x, p.y, p.sum());
+	Point q = Point(10, 20);
+	io:print("%d\n", q.sum());
+	Counter c = Counter("hits", 5);
+	io:print("%s %d\n", c.label, c.count);
+	Both b = Both(7);
+	io:print("%d %d\n", b.a, b.b);
+	return 0;
+}
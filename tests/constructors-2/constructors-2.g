@@ -0,0 +1,13 @@
+is main
+include "io"
+
+class Point(x, x) {
+	int x;
+	int y;
+}
+
+func main int {
+	Point p = Point(3, 4);
+	io:print("%d %d\n", p.x, p.y);
+	return 0;
+}
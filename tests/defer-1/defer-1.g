@@ -0,0 +1,70 @@
+is main
+include "io"
+
+func order {
+	defer io:print("1\n");
+	defer io:print("2\n");
+	io:print("body\n");
+}
+
+func early(int n) int {
+	defer io:print("cleanup %d\n", n);
+	if n > 2 {
+		return n * 10;
+	}
+	io:print("late\n");
+	return n;
+}
+
+func twice(int n) int = n * 2
+
+func loops {
+	for int i = 0; i < 4; i = i + 1 {
+		defer io:print("end %d\n", i);
+		if i == 1 {
+			continue;
+		}
+		if i == 2 {
+			break;
+		}
+		io:print("iter %d\n", i);
+	}
+}
+
+func seen int {
+	int count = 0;
+	if count == 0 {
+		defer {
+			count = count + 1;
+			io:print("block %d\n", count);
+		}
+		count = 5;
+	}
+	return count;
+}
+
+func twoLoops(int n) {
+	defer {
+		for int i = 0; i < 2; i = i + 1 {
+			io:print("%d ", i);
+		}
+		io:print("\n");
+	}
+	if n > 1 {
+		return;
+	}
+	io:print("small ");
+}
+
+func main int {
+	defer io:print("main done\n");
+	order();
+	io:print("%d\n", early(3));
+	io:print("%d\n", early(1));
+	loops();
+	io:print("%d\n", seen());
+	twoLoops(3);
+	twoLoops(0);
+	defer io:print("twice %d\n", twice(4));
+	return 0;
+}
@@ -0,0 +1,10 @@
+is main
+include "io"
+
+func main int {
+	defer {
+		io:print("done\n");
+		return 1;
+	}
+	return 0;
+}
@@ -0,0 +1,12 @@
+is main
+
+include "io"
+
+func main int {
+	int total = 0
+	for int i = 0; i < 10; i += 1 {
+		total += i
+	}
+	io:print("%d\n", totl)
+	return 0
+}
@@ -0,0 +1,12 @@
+is main
+
+include "io"
+
+class vector {
+	float x
+	float y
+}
+
+func main int {
+	return 0
+}
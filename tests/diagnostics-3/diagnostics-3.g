@@ -0,0 +1,8 @@
+is main
+
+include "io"
+
+func main int {
+	io:prnt("hello\n")
+	return 0
+}
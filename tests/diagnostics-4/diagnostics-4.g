@@ -0,0 +1,14 @@
+is main
+
+include "io"
+
+class Person {
+	int age
+}
+
+func main int {
+	Persn p
+	p.age = 30
+	io:print("%d\n", p.age)
+	return 0
+}
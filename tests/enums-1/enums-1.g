@@ -0,0 +1,51 @@
+is main
+include "io"
+
+enum Color { Red, Green, Blue }
+
+enum Shape {
+	Circle(float r),
+	Rect(float w, float h),
+	Point,
+}
+
+class Item {
+	string label;
+	Shape shape;
+}
+
+func area(Shape s) float {
+	match s {
+		Shape:Circle(r) -> return 3.0 * r * r;
+		Shape:Rect(w, h) -> return w * h;
+		_ -> return 0.0;
+	}
+	return 0.0;
+}
+
+func name(Color c) string {
+	return info(Color).variants[c as int];
+}
+
+func main int {
+	Color c = Color:Blue;
+	io:print("%s %d\n", name(c), info(Color).variant_count);
+
+	match c {
+		Color:Red, Color:Green -> io:print("warm\n");
+		Color:Blue -> io:print("cool\n");
+	}
+
+	Shape* shapes = [Shape:Circle(2.0), Shape:Rect(2.0, 3.5), Shape:Point];
+	for int i = 0; i < 3; i = i + 1 {
+		io:print("%s %.1f\n", info(Shape).variants[shapes[i] as int], area(shapes[i]));
+	}
+
+	Item item = Item();
+	item.label = "box";
+	item.shape = Shape:Rect(1.0, 4.0);
+	match item.shape {
+		Shape:Rect(_, h) -> io:print("%s %.1f\n", item.label, h);
+	}
+	return 0;
+}
@@ -0,0 +1,15 @@
+is main
+include "io"
+
+enum Message {
+	Ping,
+	Data(int id, string body),
+}
+
+func main int {
+	Message m = Message:Data(1, "hello");
+	match m {
+		Message:Data(id) -> io:print("%d\n", id);
+	}
+	return 0;
+}
@@ -0,0 +1,23 @@
+is main
+
+include "io"
+include "shapes.g"
+
+enum Shape {
+	Circle(float r),
+	Square(float side)
+}
+
+func area(Shape s) float {
+	match s {
+		Shape:Circle(r) -> return 3.0 * r * r
+		Shape:Square(side) -> return side * side
+	}
+	return 0.0
+}
+
+func main int {
+	io:print("%g %g\n", area(Shape:Circle(1.0)), area(Shape:Square(2.0)))
+	io:print("%s\n", shapes:describe(shapes:dot(3)))
+	return 0
+}
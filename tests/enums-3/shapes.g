@@ -0,0 +1,17 @@
+is shapes
+
+# Not the same enum as main:Shape
+enum Shape {
+	Dot(int size),
+	Line(int length, int width)
+}
+
+func describe(Shape s) string {
+	match s {
+		Shape:Dot(size) -> return "dot"
+		Shape:Line(length, width) -> return "line"
+	}
+	return ""
+}
+
+func dot(int size) Shape = Shape:Dot(size)
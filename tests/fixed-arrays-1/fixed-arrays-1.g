@@ -0,0 +1,22 @@
+is main
+
+include "io"
+
+func sum(int[3] a) int {
+	a[0] = 100
+	return a[0] + a[1] + a[2]
+}
+
+func main int {
+	int[3] a = [1, 2, 3]
+	# Arrays are values, so assigning and passing one copies it
+	int[3] b = a
+	b[0] = 10
+	io:print("%d %d\n", a[0], b[0])
+	io:print("%d %d\n", sum(a), a[0])
+	for x in b {
+		io:print("%d ", x)
+	}
+	io:print("%d\n", len(b))
+	return 0
+}
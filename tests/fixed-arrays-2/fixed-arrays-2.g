@@ -0,0 +1,11 @@
+is main
+
+include "io"
+
+func main int {
+	int[3] a = [1, 2, 3]
+	i = 3
+	io:print("%d\n", a[2])
+	io:print("%d\n", a[i])
+	return 0
+}
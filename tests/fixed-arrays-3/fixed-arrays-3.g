@@ -0,0 +1,9 @@
+is main
+
+include "io"
+
+func main int {
+	int[3] a = [1, 2, 3]
+	io:print("%d\n", a[3])
+	return 0
+}
@@ -0,0 +1,25 @@
+is main
+
+include "io"
+include "math"
+
+func half(f32 x) f32 = x * 0.5
+
+func main int {
+	f32 a = 0.1
+	f64 b = 0.1
+	io:print("%.10f %.10f\n", a, b)
+	f32 c = a * 3.0
+	io:print("%.10f\n", c)
+	io:print("%.4f %.4f\n", math:sinf(1.0), math:sqrtf(2.0))
+	io:print("%.2f\n", half(5))
+	f16 h = 1.1
+	io:print("%.6f\n", h)
+	float d = h
+	f32 e = d
+	int i = e * 10
+	io:print("%d\n", i)
+	f16 k = e * 1000.0
+	io:print("%.1f\n", k)
+	return 0
+}
@@ -0,0 +1,30 @@
+is main
+include "io" # for printing
+
+# Adds two numbers
+func add(int a, int b) int {
+	return a + b # the sum
+}
+
+class Vec(x, y) {
+	int x;
+	int y;
+	func sum int = this.x + this.y;
+}
+
+func main int {
+	int[] xs = [1, 2, 3]
+	int* p = &xs[0]
+	int n = add(1, 2) * 2
+	if n > 0 && !(n == 3) {
+		io:print("%d\n", n)
+	} else {
+		# nothing to do
+	}
+	for int i = 0; i < 3; i += 1 {
+		io:print("%d\n", xs[i])
+	}
+	io:print("%d %d\n", *p, Vec(1, 2).sum())
+
+	return 0
+}
@@ -0,0 +1,54 @@
+is main
+
+include "io"
+
+class Range(cur, end) {
+	int cur
+	int end
+
+	func has_next bool = this.cur < this.end
+
+	func next int {
+		v = this.cur
+		this.cur += 1
+		return v
+	}
+}
+
+func main int {
+	nums = [1, 2, 3]
+	for x in nums {
+		io:print("%d ", x)
+	}
+	io:print("\n")
+
+	int[] s = [10, 20, 30, 40]
+	total = 0
+	for i, x in s {
+		if i == 1 { continue }
+		if x == 40 { break }
+		io:print("%d:%d ", i, x)
+		total += x
+	}
+	io:print("= %d\n", total)
+
+	for c in "héllo" {
+		io:print("%c", c)
+	}
+	io:print("\n")
+
+	outer: for _, a in s[:2] {
+		for b in nums {
+			if b == 2 { continue outer }
+			io:print("%d-%d ", a, b)
+		}
+	}
+	io:print("\n")
+
+	Range r = Range(3, 6)
+	for v in r {
+		io:print("%d ", v)
+	}
+	io:print("\n")
+	return 0
+}
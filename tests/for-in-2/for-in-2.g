@@ -0,0 +1,15 @@
+is main
+
+include "io"
+
+class Box(value) {
+	int value
+}
+
+func main int {
+	Box b = Box(3)
+	for x in b {
+		io:print("%d\n", x)
+	}
+	return 0
+}
@@ -0,0 +1,9 @@
+is main
+
+include "io"
+
+func main int {
+	for c in {
+	}
+	return 0
+}
@@ -0,0 +1,17 @@
+is main
+
+include "io"
+
+enum Color { Red, Green, Blue }
+
+func main int {
+	c = Color:Green
+	ok = 3 > 2
+	io:print("%v %v %v %v %v\n", 42, 2.5, "str", ok, c)
+	s = "[%5v|%-6v]"(7, "ab")
+	io:print("%s %d%%\n", s, 100)
+	io:print("%*d|\n", 4, 9)
+	uint u = 7
+	io:print("%v %v\n", u, 1.0 / 3.0)
+	return 0
+}
@@ -0,0 +1,10 @@
+is main
+
+include "io"
+
+func main int {
+	name = "geode"
+	s = "%d items"(name)
+	io:print("%s\n", s)
+	return 0
+}
@@ -0,0 +1,8 @@
+is main
+
+include "io"
+
+func main int {
+	io:print("%f\n", 1, 2)
+	return 0
+}
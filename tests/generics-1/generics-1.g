@@ -0,0 +1,62 @@
+is main
+include "io"
+
+class List<T> {
+	T* items;
+	int len;
+	int cap;
+
+	func push(T v) {
+		if this.len == this.cap {
+			this.cap = this.cap * 2 + 1;
+			this.items = xrealloc(this.items as byte*, this.cap * info(T).size);
+		}
+		this.items[this.len] = v;
+		this.len = this.len + 1;
+	}
+
+	func get(int i) T = this.items[i];
+}
+
+class Pair<K, V>(key, val) {
+	K key;
+	V val;
+	func first K = this.key;
+}
+
+class Node<T> {
+	T value;
+	Node<T>* next;
+}
+
+func sum(List<int>* l) int {
+	int total = 0;
+	for int i = 0; i < l.len; i += 1 {
+		total += l.get(i);
+	}
+	return total;
+}
+
+func main int {
+	List<int> nums;
+	nums.push(1);
+	nums.push(2);
+	nums.push(39);
+	io:print("%d %d\n", nums.len, sum(&nums));
+
+	List<string> words;
+	words.push("hello");
+	words.push("world");
+	io:print("%s %s\n", words.get(0), words.get(1));
+
+	Pair<string, int> p = Pair<string, int>("answer", 42);
+	io:print("%s %d\n", p.first(), p.val);
+
+	Node<int> a;
+	Node<int> b;
+	a.value = 1;
+	b.value = 2;
+	a.next = &b;
+	io:print("%d\n", a.next.value);
+	return 0;
+}
@@ -0,0 +1,19 @@
+is main
+
+include "io"
+
+class Box<T> {
+	T val;
+}
+
+func unwrap(Box<Box<int>>* b) int {
+	return b.val.val;
+}
+
+func main int {
+	Box<Box<int>> bb;
+	bb.val.val = 7;
+	Box<Box<int>>* p = &bb;
+	io:print("%d %d\n", bb.val.val, unwrap(p));
+	return 0;
+}
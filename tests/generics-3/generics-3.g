@@ -0,0 +1,24 @@
+is main
+
+include "io"
+include "color"
+
+# Not the same class as color:RGB
+class RGB {
+	string name;
+}
+
+class Box<T> {
+	T val;
+}
+
+func main int {
+	Box<RGB> named;
+	Box<color:RGB> mixed;
+	named.val.name = "teal";
+	mixed.val.g = 0.5;
+	mixed.val.b = 0.5;
+	io:print("%s %g %g\n", named.val.name, mixed.val.g, mixed.val.b);
+	io:print("%s %s\n", info(Box<RGB>).name, info(Box<color:RGB>).name);
+	return 0;
+}
@@ -0,0 +1,23 @@
+is main
+
+include "io"
+
+class Point(x, y) {
+	int x;
+	int y;
+	func sum int = this.x + this.y;
+}
+
+int counter = 0;
+
+func add(int a, int b) int {
+	return a + b;
+}
+
+func main int {
+	Point p = Point(1, 2);
+	int total = add(p.x, 3);
+	counter = total;
+	io:print("%d\n", totl);
+	return 0;
+}
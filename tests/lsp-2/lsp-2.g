@@ -0,0 +1,5 @@
+is main
+
+class Point {
+	int x
+	int
@@ -0,0 +1,43 @@
+is main
+include "io"
+include "str"
+
+func kind(byte c) string {
+	match c {
+		'a', 'e', 'i', 'o', 'u' -> return "vowel";
+		' ' -> return "space";
+		_ -> return "other";
+	}
+	return "unreachable";
+}
+
+func command(string cmd) int {
+	match cmd {
+		"start" -> return 1;
+		"stop", "halt" -> return 2;
+	}
+	return 0;
+}
+
+func main int {
+	for int i = 0; i < 5; i = i + 1 {
+		match i {
+			0 -> io:print("zero ");
+			1, 2 -> {
+				io:print("small ");
+			}
+			-1 -> io:print("negative ");
+			_ -> {
+				io:print("big ");
+				if i == 3 {
+					continue;
+				}
+				io:print("! ");
+			}
+		}
+	}
+	io:print("\n");
+	io:print("%s %s %s\n", kind('e'), kind(' '), kind('z'));
+	io:print("%d %d %d\n", command("start"), command("halt"), command("go"));
+	return 0;
+}
@@ -0,0 +1,11 @@
+is main
+include "io"
+
+func main int {
+	int n = 4;
+	match n {
+		1, 4 -> io:print("one or four\n");
+		2, 4 -> io:print("two or four\n");
+	}
+	return 0;
+}
@@ -0,0 +1,11 @@
+is main
+include "io"
+
+func main int {
+	byte b = 255;
+	match b {
+		255 -> io:print("all ones\n");
+		-1 -> io:print("minus one\n");
+	}
+	return 0;
+}
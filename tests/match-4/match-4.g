@@ -0,0 +1,18 @@
+is main
+include "io"
+
+func main int {
+	byte b = 200;
+	match b {
+		-1 -> io:print("all ones\n");
+		200 -> io:print("two hundred\n");
+		_ -> io:print("something else\n");
+	}
+	b = 255;
+	match b {
+		-1 -> io:print("all ones\n");
+		200 -> io:print("two hundred\n");
+		_ -> io:print("something else\n");
+	}
+	return 0;
+}
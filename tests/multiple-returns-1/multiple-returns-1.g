@@ -0,0 +1,28 @@
+is main
+include "io"
+
+func divmod(int a, int b) (int, int) {
+	return a / b, a % b;
+}
+
+func swap(int a, string b) (string, int) = b, a
+
+func first(int a, int b) (int, int) = divmod(a, b)
+
+func widen(byte b) (long, int) {
+	return b, b;
+}
+
+func main int {
+	q, r := divmod(7, 2);
+	io:print("%d %d\n", q, r);
+	s, n := swap(4, "four");
+	io:print("%s %d\n", s, n);
+	_, r = divmod(9, 4);
+	io:print("%d %d\n", q, r);
+	q, _ = first(20, 6);
+	io:print("%d\n", q);
+	l, i := widen(100 as byte);
+	io:print("%d %d\n", l, i);
+	return 0;
+}
@@ -0,0 +1,9 @@
+is main
+include "io"
+
+func divmod(int a, int b) (int, int) = a / b, a % b
+
+func main int {
+	q, r, s := divmod(7, 2);
+	return 0;
+}
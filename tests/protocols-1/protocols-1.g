@@ -0,0 +1,60 @@
+is main
+include "io"
+
+protocol Shape {
+	func area int;
+	func scale(int by) int;
+	func name string;
+}
+
+class Square {
+	int side;
+	func area int = this.side * this.side;
+	func scale(int by) int = this.area() * by;
+	func name string = "square";
+}
+
+class Rect {
+	int w;
+	int h;
+	func area int = this.w * this.h;
+	func scale(int by) int = this.area() * by;
+	func name string = "rect";
+}
+
+protocol Addable {
+	func add(Addable a) int;
+	func value int;
+}
+
+class Num {
+	int n;
+	func add(Addable other) int = this.n + other.value();
+	func value int = this.n;
+}
+
+func describe(Shape s) {
+	io:print("%s %d %d\n", s.name(), s.area(), s.scale(2));
+}
+
+func sum(Addable a, Addable b) int = a.add(b);
+
+func main int {
+	Square sq;
+	sq.side = 3;
+	Rect r;
+	r.w = 2;
+	r.h = 5;
+	describe(sq);
+	describe(r);
+
+	Shape s = r;
+	io:print("%d\n", s.area());
+
+	Num a;
+	a.n = 4;
+	Num b;
+	b.n = 38;
+	io:print("%d\n", sum(a, b));
+	return 0;
+}
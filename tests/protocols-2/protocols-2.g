@@ -0,0 +1,19 @@
+is main
+
+protocol Addable {
+	func add(Addable a) int;
+	func value int;
+}
+
+class Num {
+	int n;
+	func value int = this.n;
+}
+
+func sum(Addable a, Addable b) int = a.add(b);
+
+func main int {
+	Num a;
+	a.n = 1;
+	return sum(a, a);
+}
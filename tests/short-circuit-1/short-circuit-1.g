@@ -0,0 +1,57 @@
+is main
+include "io"
+
+class Node {
+	int value;
+	Node* next;
+}
+
+int calls = 0;
+
+func touch(int v) int {
+	calls = calls + 1;
+	return v;
+}
+
+func main int {
+	# the right hand side would crash if it was evaluated
+	Node* empty = nil;
+	if empty != nil && empty.value > 0 {
+		io:print("unreachable\n");
+	}
+
+	# skipped calls
+	if 0 && touch(1) {
+		io:print("unreachable\n");
+	}
+	if 1 || touch(1) {
+		io:print("or %d\n", calls);
+	}
+	if 1 && touch(1) {
+		io:print("and %d\n", calls);
+	}
+
+	# operands of different widths, and values that truncate to zero
+	byte small = 2;
+	long wide = 256;
+	if small && wide {
+		io:print("widths\n");
+	}
+	if 2 && 1 {
+		io:print("two\n");
+	}
+
+	# conditions of loops
+	int i = 0;
+	while i < 10 && touch(i) != 4 {
+		i = i + 1;
+	}
+	io:print("while %d\n", i);
+
+	int sum = 0;
+	for int j = 0; j < 10 && (j < 3 || j == 5); j = j + 1 {
+		sum = sum + j;
+	}
+	io:print("for %d\n", sum);
+	return 0;
+}
@@ -0,0 +1,53 @@
+is main
+
+include "io"
+
+class Person {
+	string name;
+	byte _byte;
+	short _i16;
+	int _i32;
+	long _int;
+	float _float;
+	Person* mother;
+	Person* father;
+}
+
+class Small {
+	byte a
+	int b
+	byte c
+}
+
+class Tail(a, b) {
+	long a
+	byte b
+}
+
+class Halfs {
+	f16 a
+	byte b
+	f32 c
+}
+
+enum Shape {
+	Circle(float r),
+	Rect(byte w, float h),
+	Dot
+}
+
+func main int {
+	io:print("%d %d %d %d %d\n", info(Person).size, info(Small).size, info(Tail).size, info(Halfs).size, info(Shape).size)
+	io:print("%d %d %d %d %d %d\n", info(byte).size, info(short).size, info(int).size, info(long).size, info(big).size, info(f16).size)
+	io:print("%d %d %d %d\n", info(string).size, info(int[]).size, info(float).size, info(bool).size)
+
+	Tail[] tails = []
+	for i = 0; i < 5; i += 1 {
+		tails = append(tails, Tail(i * 10, i))
+	}
+	for t in tails {
+		io:print("%d:%d ", t.a, t.b)
+	}
+	io:print("\n")
+	return 0
+}
@@ -0,0 +1,59 @@
+is main
+include "io"
+
+func sum(int[] xs) int {
+	total = 0;
+	for int i = 0; i < len(xs); i = i + 1 {
+		total = total + xs[i];
+	}
+	return total;
+}
+
+func show(int[] xs) {
+	for int i = 0; i < len(xs); i = i + 1 {
+		io:print("%d ", xs[i]);
+	}
+	io:print("(%d)\n", len(xs));
+}
+
+func main int {
+	int[] xs = [1, 2, 3, 4, 5];
+	show(xs);
+	io:print("%d\n", sum(xs));
+
+	int lo = 1;
+	int hi = 3;
+	mid = xs[lo:hi];
+	show(mid);
+	mid[0] = 20;
+	show(xs);
+	show(xs[:2]);
+	show(xs[3:]);
+	show(xs[1:n()]);
+
+	int[] ys;
+	for int i = 0; i < 10; i = i + 1 {
+		ys = append(ys, i * i);
+	}
+	show(ys);
+	ys = append(ys, 100, 200);
+	show(ys[9:]);
+
+	tail = xs[3:];
+	tail = append(tail, 6, 7, 8);
+	show(tail);
+	show(xs);
+
+	string s = "hello world";
+	show2(s[6:11]);
+	return 0;
+}
+
+func n int = 4
+
+func show2(byte[] bs) {
+	for int i = 0; i < len(bs); i = i + 1 {
+		io:print("%c", bs[i]);
+	}
+	io:print("\n");
+}
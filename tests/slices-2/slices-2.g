@@ -0,0 +1,8 @@
+is main
+include "io"
+
+func main int {
+	string s = "hello";
+	byte[] bs = s[1:];
+	return 0;
+}
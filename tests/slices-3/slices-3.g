@@ -0,0 +1,29 @@
+is main
+include "io"
+
+func show(int[] xs) {
+	for int i = 0; i < len(xs); i = i + 1 {
+		io:print("%d ", xs[i]);
+	}
+	io:print("\n");
+}
+
+func main int {
+	int[] a;
+	a = append(a, 1, 2, 3, 4);
+
+	# b shares the storage of a until a grows, and keeps it afterwards
+	b = a;
+	for int i = 0; i < 100; i = i + 1 {
+		a = append(a, i);
+	}
+
+	# Storage freed when a grew would be handed out again here
+	for int i = 0; i < 100; i = i + 1 {
+		int[] c;
+		c = append(c, -1, -1, -1, -1);
+	}
+	show(b);
+	io:print("%d %d\n", len(a), a[103]);
+	return 0;
+}
@@ -0,0 +1,13 @@
+is main
+
+include "io"
+include "str"
+
+func main int {
+	string* parts = str:split("a,b;;cd", ",;")
+	for i = 0; parts[i] != nil; i += 1 {
+		io:print("[%s] ", parts[i])
+	}
+	io:print("\n")
+	return 0
+}
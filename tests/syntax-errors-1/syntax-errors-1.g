@@ -0,0 +1,24 @@
+is main
+
+include "io"
+
+class point(x) {
+	int x
+}
+
+func first int {
+	a = )
+	return 1
+}
+
+func second int {
+	if true {
+		b = "\q"
+	}
+	return 2
+}
+
+func main int {
+	io:print("%d %d\n", first(), second())
+	return 0
+}
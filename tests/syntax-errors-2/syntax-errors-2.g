@@ -0,0 +1,9 @@
+is main
+
+include "io"
+
+func main int {
+	x = "abcdef"[1:
+	io:print("%s\n", x)
+	return 0
+}
@@ -0,0 +1,16 @@
+is main
+
+# A field without a name
+class Foo {
+	int
+}
+
+# A method that is still being typed
+class Bar {
+	int x
+	func init(
+}
+
+func main int {
+	return 0
+}
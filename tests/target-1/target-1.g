@@ -0,0 +1,13 @@
+is main
+
+include "io"
+
+class Pair {
+	byte a
+	long b
+}
+
+func main int {
+	io:print("%d\n", info(Pair).size)
+	return 0
+}
@@ -0,0 +1,50 @@
+is main
+
+include "io"
+
+class Person {
+	string name
+	byte age
+	long id
+	Person* mother
+	float[] scores
+}
+
+enum Color { Red, Green }
+
+func show(TypeInfo* t) {
+	io:print("%s size=%d align=%d kind=%d", t.name, t.size, t.align, t.kind as int)
+	if t.elem != nil {
+		io:print(" elem=%s", t.elem.name)
+	}
+	io:print("\n")
+	for i = 0; i < t.field_count; i += 1 {
+		f = t.fields[i]
+		io:print("  %s @%d: %s\n", f.name, f.offset, f.type.name)
+	}
+}
+
+func kindName(TypeInfo* t) string {
+	match t.kind {
+		TypeKind:Class -> return "class"
+		TypeKind:Pointer -> return "pointer"
+		TypeKind:String -> return "string"
+		_ -> return "other"
+	}
+	return ""
+}
+
+func size(T? v) int {
+	return info(T).size
+}
+
+func main int {
+	show(info(Person))
+	show(info(Person*))
+	show(info(int[]))
+	show(info(Color))
+	show(info(uint))
+	io:print("%s %s %s\n", kindName(info(Person)), kindName(info(Person).fields[3].type), kindName(info(string)))
+	io:print("%d %d\n", size(1 as byte), size(2 as long))
+	return 0
+}
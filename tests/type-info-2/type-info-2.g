@@ -0,0 +1,17 @@
+is main
+
+include "io"
+
+func show(TypeInfo* t) {
+	io:print("%s kind=%d elem=%s\n", t.name, t.kind as int, t.elem.name)
+}
+
+func main int {
+	# string and ubyte* are both i8* to llvm
+	show(info(string))
+	show(info(ubyte*))
+	show(info(byte*))
+	show(info(byte[4]))
+	show(info(ubyte[4]))
+	return 0
+}
@@ -0,0 +1,47 @@
+is main
+
+include "io"
+
+# 32 bit FNV-1a
+func hash(string s) uint {
+	uint h = 2166136261
+	for c in s {
+		h = h ^ (c as ubyte)
+		h = h * 16777619
+	}
+	return h
+}
+
+func main int {
+	uint a = 4000000000
+	uint b = 3
+	io:print("%u %u %u\n", a / b, a % 7, a >> 4)
+
+	int n = -16
+	io:print("%d %d %d\n", n / 3, n % 3, n >> 2)
+
+	if a > b {
+		io:print("unsigned compare\n")
+	}
+	uint m = -1
+	if m > 0 {
+		io:print("%u\n", m)
+	}
+
+	ubyte x = 200
+	int y = x
+	io:print("%d\n", y)
+
+	float f = a
+	io:print("%.0f\n", f)
+	uint g = 3000000000.0
+	io:print("%u\n", g)
+
+	ulong l = 0xffffffffffffffff
+	if l > 0 {
+		io:print("%u\n", l >> 40)
+	}
+
+	io:print("%u\n", hash("geode"))
+	return 0
+}
@@ -0,0 +1,36 @@
+is main
+include "io"
+
+pure square(int x) int = x * x
+
+func sign(int x) int {
+	if x > 0 {
+		return 1
+	} else {
+		return 0
+	}
+}
+
+func half(int x, int unused) int {
+	if x > 0 {
+		return 1
+	}
+}
+
+func main int {
+	int n = 3
+	int spare = 4
+	int overwritten = 4
+	overwritten = 5
+	if n > 0 {
+		int n = 5
+		io:print("%d\n", n)
+	}
+	if n = 4 {
+		io:print("assigned\n")
+	}
+	square(n)
+	io:print("%d %d %d\n", sign(n), half(n, 1), square(2))
+	return 0
+	io:print("never\n")
+}